@@ -0,0 +1,113 @@
+package events
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var defaultDateKeys = []string{"date", "g_date"}
+
+var defaultDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"01/02/2006 15:04:05",
+}
+
+type AbsoluteClockOptions struct {
+	// DateKeys are the InitGame keys checked for an absolute date, in order.
+	// A key holding a number is read as Unix seconds.
+	DateKeys []string
+	Layouts  []string
+	Location *time.Location
+
+	// Extract, if set, replaces the InitGame lookup. It is offered every
+	// event and returns the absolute time of that event when it carries one,
+	// e.g. a dedicated date line at map start.
+	Extract func(Event) (time.Time, bool)
+}
+
+// AbsoluteClock reconstructs absolute times from a log's relative game-clock
+// timestamps. When an event carrying an absolute date is seen, it becomes the
+// epoch for the following events, whose time is the epoch plus the game-clock
+// time elapsed since it. Every InitGame starts a new match; if it carries no
+// date, times are unavailable until one is seen.
+type AbsoluteClock struct {
+	opts AbsoluteClockOptions
+
+	mu      sync.Mutex
+	epoch   time.Time
+	epochTs time.Duration
+	ok      bool
+}
+
+func NewAbsoluteClock(opts AbsoluteClockOptions) *AbsoluteClock {
+	if len(opts.DateKeys) == 0 {
+		opts.DateKeys = defaultDateKeys
+	}
+	if len(opts.Layouts) == 0 {
+		opts.Layouts = defaultDateLayouts
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &AbsoluteClock{opts: opts}
+}
+
+// Resolve updates the epoch from e if it carries a date and returns the
+// absolute time of e. It returns false when e has no timestamp or no epoch
+// is known for the current match.
+func (c *AbsoluteClock) Resolve(e Event) (time.Time, bool) {
+	ts := e.GetTimestamp()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if isInitGame(e) {
+		c.ok = false
+	}
+
+	if at, found := c.extract(e); found {
+		c.epoch = at
+		c.epochTs = 0
+		if ts != nil {
+			c.epochTs = *ts
+		}
+		c.ok = true
+	}
+
+	if !c.ok || ts == nil {
+		return time.Time{}, false
+	}
+	return c.epoch.Add(*ts - c.epochTs), true
+}
+
+func (c *AbsoluteClock) extract(e Event) (time.Time, bool) {
+	if c.opts.Extract != nil {
+		return c.opts.Extract(e)
+	}
+
+	s, ok := e.(*ServerEvent)
+	if !ok || s.Command != CmdInitGame {
+		return time.Time{}, false
+	}
+
+	for _, key := range c.opts.DateKeys {
+		v := strings.TrimSpace(s.Data[key])
+		if v == "" {
+			continue
+		}
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(secs, 0).In(c.opts.Location), true
+		}
+		for _, layout := range c.opts.Layouts {
+			if at, err := time.ParseInLocation(layout, v, c.opts.Location); err == nil {
+				return at, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
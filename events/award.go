@@ -0,0 +1,87 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AwardEvent struct {
+	BaseEvent
+	ClientNum int
+
+	// AwardType is the mod-specific award token as logged.
+	AwardType string
+
+	// Count is the trailing count field, or 1 when the line has none.
+	Count int
+}
+
+// Name returns the friendly name registered for the award type, or the raw
+// award type if none is registered.
+func (e *AwardEvent) Name() string {
+	return AwardName(e.AwardType)
+}
+
+var (
+	awardNameMu sync.RWMutex
+	awardNames  = map[string]string{}
+)
+
+// RegisterAwardName maps a mod-specific award type to a friendly name.
+func RegisterAwardName(awardType, name string) {
+	awardNameMu.Lock()
+	awardNames[awardType] = name
+	awardNameMu.Unlock()
+}
+
+// AwardName returns the friendly name registered for awardType, or awardType
+// itself if none is registered.
+func AwardName(awardType string) string {
+	awardNameMu.RLock()
+	name, ok := awardNames[awardType]
+	awardNameMu.RUnlock()
+	if !ok {
+		return awardType
+	}
+	return name
+}
+
+// parseAwardEvent parses "Award: <num> <awardtype> [count]".
+func parseAwardEvent(line string, ts *time.Duration, raw string) (*AwardEvent, error) {
+	cmd, rest, ok := strings.Cut(line, ":")
+	if !ok || cmd != CmdAward {
+		return nil, fmt.Errorf("not an award event")
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("invalid award event line: %q", line)
+	}
+
+	clientNum, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", fields[0], err)
+	}
+
+	count := 1
+	if len(fields) == 3 {
+		count, err = strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid award count %q: %w", fields[2], err)
+		}
+	}
+
+	return &AwardEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   CmdAward,
+			Raw:       raw,
+		},
+		ClientNum: clientNum,
+		AwardType: fields[1],
+		Count:     count,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Batch groups events from in into slices of at most maxBatch events. A batch
+// is sent when it is full or maxDelay after its first event arrived,
+// whichever comes first. A final partial batch is sent when in is closed. A
+// non-positive maxDelay only flushes full batches and the final one.
+func Batch(ctx context.Context, in <-chan Event, maxBatch int, maxDelay time.Duration) <-chan []Event {
+	return batchEvents(ctx, in, maxBatch, maxDelay, realClock{})
+}
+
+func batchEvents(ctx context.Context, in <-chan Event, maxBatch int, maxDelay time.Duration, clk clock) <-chan []Event {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+	out := make(chan []Event)
+
+	go func() {
+		defer close(out)
+
+		// deadline fires maxDelay after the first event of the batch.
+		var deadline <-chan time.Time
+
+		var batch []Event
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			deadline = nil
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- b:
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline:
+				if !flush() {
+					return
+				}
+			case e, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if len(batch) == 0 && maxDelay > 0 {
+					deadline = clk.After(maxDelay)
+				}
+				batch = append(batch, e)
+				if len(batch) >= maxBatch && !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
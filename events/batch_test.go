@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func receiveBatch(t *testing.T, out <-chan []Event) []Event {
+	t.Helper()
+	select {
+	case b := <-out:
+		return b
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a batch")
+		return nil
+	}
+}
+
+func TestBatchFlushesAfterMaxDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := newFakeClock()
+	in := make(chan Event)
+	out := batchEvents(ctx, in, 3, time.Second, clk)
+
+	ev := func(cmd string) Event { return &BaseEvent{Command: cmd} }
+
+	in <- ev("a")
+	clk.waitForTimers(t, 1)
+	clk.Advance(500 * time.Millisecond)
+	in <- ev("b")
+	select {
+	case b := <-out:
+		t.Fatalf("got %d events before the delay elapsed", len(b))
+	default:
+	}
+
+	// The delay counts from the first event of the batch.
+	clk.Advance(500 * time.Millisecond)
+	if b := receiveBatch(t, out); len(b) != 2 || b[0].GetCommand() != "a" || b[1].GetCommand() != "b" {
+		t.Fatalf("got %v, want a and b", b)
+	}
+
+	// A full batch is sent without waiting, and the next one starts a new
+	// delay.
+	go func() {
+		for _, cmd := range []string{"c", "d", "e", "f"} {
+			in <- ev(cmd)
+		}
+		close(in)
+	}()
+	if b := receiveBatch(t, out); len(b) != 3 {
+		t.Fatalf("got %d events, want a full batch of 3", len(b))
+	}
+	if b := receiveBatch(t, out); len(b) != 1 || b[0].GetCommand() != "f" {
+		t.Fatalf("got %v, want the final partial batch", b)
+	}
+	if _, ok := <-out; ok {
+		t.Error("output not closed after the input")
+	}
+	if got := clk.lastAfter(); got != time.Second {
+		t.Errorf("delay = %v, want 1s", got)
+	}
+}
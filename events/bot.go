@@ -0,0 +1,60 @@
+package events
+
+import (
+	"strings"
+	"sync"
+)
+
+type botDecoration struct {
+	prefix, suffix string
+}
+
+var (
+	botDecorationMu sync.RWMutex
+	botDecorations  = []botDecoration{
+		{prefix: "[BOT]"},
+		{suffix: "(bot)"},
+	}
+)
+
+// RegisterBotDecoration adds a prefix and/or suffix that NormalizeBotName
+// strips, e.g. RegisterBotDecoration("", "[AI]") for "Name [AI]". Matching is
+// case-insensitive; an empty prefix or suffix matches anything.
+func RegisterBotDecoration(prefix, suffix string) {
+	prefix, suffix = strings.TrimSpace(prefix), strings.TrimSpace(suffix)
+	if prefix == "" && suffix == "" {
+		return
+	}
+	botDecorationMu.Lock()
+	botDecorations = append(botDecorations, botDecoration{prefix: prefix, suffix: suffix})
+	botDecorationMu.Unlock()
+}
+
+// NormalizeBotName returns name without the first registered bot decoration
+// it carries, so "[BOT]Ranger" and "Ranger (bot)" both become "Ranger". Color
+// codes are left alone; combine with CleanName for display. Names that carry
+// no decoration, or would be left empty, are returned trimmed.
+//
+// It only looks at the name; use IsBot on the GUID to tell whether a player
+// actually is a bot.
+func NormalizeBotName(name string) string {
+	name = strings.TrimSpace(name)
+
+	botDecorationMu.RLock()
+	defer botDecorationMu.RUnlock()
+
+	for _, d := range botDecorations {
+		if len(d.prefix)+len(d.suffix) > len(name) {
+			continue
+		}
+		head, tail := name[:len(d.prefix)], name[len(name)-len(d.suffix):]
+		if !strings.EqualFold(head, d.prefix) || !strings.EqualFold(tail, d.suffix) {
+			continue
+		}
+		stripped := strings.TrimSpace(name[len(d.prefix) : len(name)-len(d.suffix)])
+		if stripped != "" {
+			return stripped
+		}
+	}
+	return name
+}
@@ -0,0 +1,57 @@
+package events
+
+import "testing"
+
+func TestNormalizeBotName(t *testing.T) {
+	tests := map[string]string{
+		"[BOT]Ranger":   "Ranger",
+		"[bot] Ranger ": "Ranger",
+		"Ranger (bot)":  "Ranger",
+		"Ranger (BOT)":  "Ranger",
+		"^1[BOT]Ranger": "^1[BOT]Ranger",
+		"Robot":         "Robot",
+		"[BOT]":         "[BOT]",
+		" (bot) ":       "(bot)",
+		"[BOT]Ra (bot)": "Ra (bot)",
+		"":              "",
+	}
+	for name, want := range tests {
+		if got := NormalizeBotName(name); got != want {
+			t.Errorf("NormalizeBotName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRegisterBotDecoration(t *testing.T) {
+	// The registry is global; no other test uses this suffix.
+	RegisterBotDecoration(" ", " <<ai>> ")
+	RegisterBotDecoration("", "")
+	if got := NormalizeBotName("Ranger <<AI>>"); got != "Ranger" {
+		t.Errorf("NormalizeBotName with a registered suffix = %q, want Ranger", got)
+	}
+	if got := NormalizeBotName("<<ai>>"); got != "<<ai>>" {
+		t.Errorf("NormalizeBotName(%q) = %q, want it unchanged", "<<ai>>", got)
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	tests := []struct {
+		guid string
+		want bool
+	}{
+		{"bot12", true},
+		{"BOT3", true},
+		{" bot0 ", true},
+		{"bot", false},
+		{"botx1", false},
+		{"bot1x", false},
+		{"0", false},
+		{"", false},
+		{"abot1", false},
+	}
+	for _, tt := range tests {
+		if got := IsBot(tt.guid); got != tt.want {
+			t.Errorf("IsBot(%q) = %v, want %v", tt.guid, got, tt.want)
+		}
+	}
+}
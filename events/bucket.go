@@ -0,0 +1,131 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultBucketSize = time.Minute
+
+type KillBucket struct {
+	// Epoch increments on every InitGame, since game-clock timestamps restart
+	// with each match.
+	Epoch   int
+	Start   time.Duration
+	Size    time.Duration
+	Kills   int
+	Untimed bool
+
+	// Late counts kills that arrived while this window was open but belong
+	// to an earlier, already flushed window. They are not in Kills.
+	Late int
+}
+
+type BucketOptions struct {
+	Size time.Duration
+
+	// DropUntimed ignores kills without a timestamp. By default they are
+	// counted in a separate bucket with Untimed set, flushed when the epoch
+	// ends or Flush is called.
+	DropUntimed bool
+
+	OnFlush func(KillBucket)
+}
+
+// TimeBucketer counts kill events per fixed-size window of the game clock and
+// hands each completed window to OnFlush. A window is complete once a kill
+// arrives in a later window; empty windows in between are flushed with zero
+// kills. A flushed window is never reopened: a kill logged out of order for
+// an earlier window is counted in the open window's Late instead.
+type TimeBucketer struct {
+	opts BucketOptions
+
+	mu      sync.Mutex
+	epoch   int
+	current *KillBucket
+	untimed int
+}
+
+func NewTimeBucketer(opts BucketOptions) *TimeBucketer {
+	if opts.Size <= 0 {
+		opts.Size = defaultBucketSize
+	}
+	return &TimeBucketer{opts: opts}
+}
+
+func (b *TimeBucketer) ApplyEvent(e Event) {
+	var flushed []KillBucket
+
+	b.mu.Lock()
+	switch t := e.(type) {
+	case *ServerEvent:
+		if t.Command == CmdInitGame {
+			flushed = b.endEpochLocked()
+			b.epoch++
+		}
+	case *KillEvent:
+		flushed = b.addKillLocked(t)
+	}
+	b.mu.Unlock()
+
+	b.emit(flushed)
+}
+
+func (b *TimeBucketer) Flush() {
+	b.mu.Lock()
+	flushed := b.endEpochLocked()
+	b.mu.Unlock()
+
+	b.emit(flushed)
+}
+
+func (b *TimeBucketer) addKillLocked(k *KillEvent) []KillBucket {
+	if k.Timestamp == nil {
+		if !b.opts.DropUntimed {
+			b.untimed++
+		}
+		return nil
+	}
+
+	start := k.Timestamp.Truncate(b.opts.Size)
+	if b.current != nil && start < b.current.Start {
+		b.current.Late++
+		return nil
+	}
+
+	var flushed []KillBucket
+	if b.current != nil && b.current.Start != start {
+		flushed = append(flushed, *b.current)
+		for gap := b.current.Start + b.opts.Size; gap < start; gap += b.opts.Size {
+			flushed = append(flushed, KillBucket{Epoch: b.epoch, Start: gap, Size: b.opts.Size})
+		}
+		b.current = nil
+	}
+	if b.current == nil {
+		b.current = &KillBucket{Epoch: b.epoch, Start: start, Size: b.opts.Size}
+	}
+	b.current.Kills++
+	return flushed
+}
+
+func (b *TimeBucketer) endEpochLocked() []KillBucket {
+	var flushed []KillBucket
+	if b.current != nil {
+		flushed = append(flushed, *b.current)
+		b.current = nil
+	}
+	if b.untimed > 0 {
+		flushed = append(flushed, KillBucket{Epoch: b.epoch, Size: b.opts.Size, Kills: b.untimed, Untimed: true})
+		b.untimed = 0
+	}
+	return flushed
+}
+
+func (b *TimeBucketer) emit(buckets []KillBucket) {
+	if b.opts.OnFlush == nil {
+		return
+	}
+	for _, bucket := range buckets {
+		b.opts.OnFlush(bucket)
+	}
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeBucketer(t *testing.T) {
+	var got []KillBucket
+	b := NewTimeBucketer(BucketOptions{OnFlush: func(k KillBucket) { got = append(got, k) }})
+
+	lines := []string{
+		"0:10 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"0:50 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"1:10 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"0:55 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"3:05 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"3:30 InitGame: \\g_gametype\\dm",
+		"0:05 K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+	}
+	for _, line := range lines {
+		e, err := ParseEventLine(line)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		b.ApplyEvent(e)
+	}
+	b.Flush()
+
+	want := []KillBucket{
+		{Epoch: 0, Start: 0, Size: time.Minute, Kills: 2},
+		{Epoch: 0, Start: time.Minute, Size: time.Minute, Kills: 1, Late: 1},
+		{Epoch: 0, Start: 2 * time.Minute, Size: time.Minute},
+		{Epoch: 0, Start: 3 * time.Minute, Size: time.Minute, Kills: 1},
+		{Epoch: 0, Size: time.Minute, Kills: 1, Untimed: true},
+		{Epoch: 1, Start: 0, Size: time.Minute, Kills: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buckets:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestTimeBucketerDropUntimed(t *testing.T) {
+	var got []KillBucket
+	b := NewTimeBucketer(BucketOptions{DropUntimed: true, OnFlush: func(k KillBucket) { got = append(got, k) }})
+	b.ApplyEvent(&KillEvent{})
+	b.Flush()
+	if len(got) != 0 {
+		t.Errorf("got %+v, want no buckets", got)
+	}
+}
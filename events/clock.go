@@ -0,0 +1,27 @@
+package events
+
+import "time"
+
+// clock abstracts the time source of the time-dependent types, so that they
+// can be driven by a fake in tests.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) ticker
+}
+
+type ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) Chan() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()                  { r.t.Stop() }
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when Advance is called.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []fakeTimer
+	tickers []*fakeTicker
+	afters  []time.Duration
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+type fakeTicker struct {
+	clk     *fakeClock
+	period  time.Duration
+	next    time.Time
+	c       chan time.Time
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.afters = append(c.afters, d)
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{clk: c, period: d, next: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time { return t.c }
+
+func (t *fakeTicker) Stop() {
+	t.clk.mu.Lock()
+	t.stopped = true
+	t.clk.mu.Unlock()
+}
+
+// Advance moves the clock forward by d, firing the timers and tickers that
+// become due. Like a time.Ticker, a ticker whose tick was not received
+// drops the following ones.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+
+	for _, t := range c.tickers {
+		for !t.stopped && !t.next.After(c.now) {
+			select {
+			case t.c <- c.now:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
+
+// waitForTimers blocks until at least n timers are pending, i.e. until the
+// goroutines under test are waiting on the clock.
+func (c *fakeClock) waitForTimers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		pending := len(c.timers)
+		c.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d pending timers, have %d", n, pending)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// lastAfter returns the duration of the most recent After call.
+func (c *fakeClock) lastAfter() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.afters) == 0 {
+		return 0
+	}
+	return c.afters[len(c.afters)-1]
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	clk := newFakeClock()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+
+	clk.Advance(999 * time.Millisecond)
+	select {
+	case <-tk.Chan():
+		t.Fatal("ticker fired early")
+	default:
+	}
+
+	clk.Advance(time.Millisecond)
+	select {
+	case <-tk.Chan():
+	default:
+		t.Fatal("ticker did not fire")
+	}
+}
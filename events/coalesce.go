@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type CoalesceOptions struct {
+	// Window is how soon a repeat must follow the previous line.
+	Window time.Duration
+
+	// Collation selects how messages are compared, after color codes are
+	// removed and whitespace is collapsed. The default, CollationSimple,
+	// lowercases them with strings.ToLower.
+	Collation NameCollation
+}
+
+// CoalesceChat collapses repeated chat lines from the same player into a
+// single ChatEvent whose Repeat counts the lines. A repeat must arrive within
+// window of the previous one; log timestamps are compared when both lines
+// carry one, otherwise arrival time is used. Any other event, or a chat line
+// from a different player or with a different message, flushes the pending
+// ChatEvent first so that ordering is preserved. Non-chat events pass through.
+func CoalesceChat(ctx context.Context, in <-chan Event, window time.Duration) <-chan Event {
+	return CoalesceChatWithOptions(ctx, in, CoalesceOptions{Window: window})
+}
+
+func CoalesceChatWithOptions(ctx context.Context, in <-chan Event, opts CoalesceOptions) <-chan Event {
+	return coalesceChat(ctx, in, opts, realClock{})
+}
+
+func coalesceChat(ctx context.Context, in <-chan Event, opts CoalesceOptions, clk clock) <-chan Event {
+	out := make(chan Event)
+	window := opts.Window
+
+	go func() {
+		defer close(out)
+
+		// deadline fires window after the last line of the pending ChatEvent.
+		var deadline <-chan time.Time
+		var pending *ChatEvent
+		var lastTs *time.Duration
+		var lastArrival time.Time
+
+		send := func(e Event) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- e:
+				return true
+			}
+		}
+		flush := func() bool {
+			if pending == nil {
+				return true
+			}
+			p := pending
+			pending = nil
+			deadline = nil
+			return send(p)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline:
+				if !flush() {
+					return
+				}
+			case e, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				p, isChat := e.(*PlayerEvent)
+				if !isChat || !isChatCommand(p.Command) {
+					if !flush() || !send(e) {
+						return
+					}
+					continue
+				}
+
+				now := clk.Now()
+				if pending != nil && sameChat(&pending.PlayerEvent, p, opts.Collation) &&
+					withinWindow(lastTs, p.Timestamp, lastArrival, now, window) {
+					pending.Repeat++
+					lastTs = p.Timestamp
+					lastArrival = now
+					deadline = clk.After(window)
+					continue
+				}
+
+				if !flush() {
+					return
+				}
+				pending = &ChatEvent{PlayerEvent: *p, Repeat: 1}
+				lastTs = p.Timestamp
+				lastArrival = now
+				deadline = clk.After(window)
+			}
+		}
+	}()
+
+	return out
+}
+
+func isChatCommand(cmd string) bool {
+	return cmd == CmdSay || cmd == CmdSayTeam
+}
+
+func sameChat(a, b *PlayerEvent, collation NameCollation) bool {
+	if a.Command != b.Command {
+		return false
+	}
+	if a.XUID != "" && b.XUID != "" {
+		if a.XUID != b.XUID {
+			return false
+		}
+	} else if a.Player != b.Player {
+		return false
+	}
+	return normalizeChat(a.Message, collation) == normalizeChat(b.Message, collation)
+}
+
+func normalizeChat(msg string, collation NameCollation) string {
+	return collate(strings.Join(strings.Fields(stripColorCodes(msg)), " "), collation)
+}
+
+func withinWindow(prevTs, ts *time.Duration, prevArrival, arrival time.Time, window time.Duration) bool {
+	if prevTs != nil && ts != nil {
+		d := *ts - *prevTs
+		return d >= 0 && d <= window
+	}
+	return arrival.Sub(prevArrival) <= window
+}
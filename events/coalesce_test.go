@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCoalesceChatFlushesAfterWindow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := newFakeClock()
+	in := make(chan Event)
+	out := coalesceChat(ctx, in, CoalesceOptions{Window: time.Second}, clk)
+
+	for i := 0; i < 2; i++ {
+		ev, err := ParseEventLine("say;aa;1;Alice;gg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		in <- ev
+		// The first line's timer is still pending when the second arrives.
+		clk.waitForTimers(t, i+1)
+		if i == 0 {
+			clk.Advance(500 * time.Millisecond)
+		}
+	}
+
+	select {
+	case e := <-out:
+		t.Fatalf("got %v before the window elapsed", e)
+	default:
+	}
+
+	clk.Advance(time.Second)
+	select {
+	case e := <-out:
+		chat, ok := e.(*ChatEvent)
+		if !ok || chat.Repeat != 2 {
+			t.Fatalf("got %#v, want a ChatEvent with Repeat 2", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending chat not flushed after the window")
+	}
+}
+
+func TestSameChatFoldsCase(t *testing.T) {
+	a := &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, XUID: "aa", Message: "^1IŞIK  geldi"}
+	b := &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, XUID: "aa", Message: "ışık geldi"}
+	if !sameChat(a, b, CollationFold) {
+		t.Error("messages differing in case and spacing did not match")
+	}
+	b.Message = "ışık gitti"
+	if sameChat(a, b, CollationFold) {
+		t.Error("different messages matched")
+	}
+}
+
+func TestSameChatSimpleCollationByDefault(t *testing.T) {
+	a := &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, XUID: "aa", Message: "^1GG  Well Played"}
+	b := &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, XUID: "aa", Message: "gg well played"}
+	if !sameChat(a, b, CoalesceOptions{}.Collation) {
+		t.Error("messages differing in case and spacing did not match")
+	}
+	// strings.ToLower keeps "ı" apart from "i".
+	a.Message, b.Message = "IŞIK", "ışık"
+	if sameChat(a, b, CollationSimple) {
+		t.Error("dotless i folded without CollationFold")
+	}
+}
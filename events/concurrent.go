@@ -0,0 +1,187 @@
+package events
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+const defaultMaxInFlightPerWorker = 64
+
+type ConcurrentTailOptions struct {
+	TailOptions
+
+	// Workers is the number of goroutines parsing lines. Zero uses
+	// runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Ordered delivers events in file order. Parsed events wait in a reorder
+	// buffer until every earlier line has been delivered, so one slow line
+	// holds back those after it and the buffer can fill up to MaxInFlight
+	// events. Unordered, each event is delivered as soon as it is parsed,
+	// with the least latency and memory, but events from nearby lines may
+	// arrive swapped.
+	Ordered bool
+
+	// MaxInFlight bounds the lines read but not yet delivered, and so the
+	// reorder buffer; reading waits once it is reached. Zero uses 64 per
+	// worker.
+	MaxInFlight int
+}
+
+// TailFileConcurrent is like TailFileWithOptions, but parses lines on a pool
+// of worker goroutines, for logs written faster than one goroutine can parse
+// them. Everything else the tailer does, from preprocessing to delivery,
+// still happens on the tailing goroutine. Lines read before end of file is
+// reached are delivered before the tailer waits for more.
+func TailFileConcurrent(ctx context.Context, path string, opts ConcurrentTailOptions, eventsCh chan<- Event) error {
+	t := newTailer(opts.TailOptions, eventsCh, realClock{})
+	t.pool = newParsePool(opts)
+	defer t.pool.close()
+	return t.run(ctx, path)
+}
+
+type parseJob struct {
+	seq  uint64
+	line string
+	opts ParseOptions
+	rl   rawLine
+}
+
+type parseResult struct {
+	parseJob
+	ev  Event
+	err error
+}
+
+// parsePool parses lines submitted by a tailer. Its channels are buffered to
+// maxInFlight, so neither the tailer nor the workers ever block on them while
+// fewer lines than that are in flight.
+type parsePool struct {
+	ordered     bool
+	maxInFlight int
+
+	jobs    chan parseJob
+	results chan parseResult
+	wg      sync.WaitGroup
+
+	// Only the tailing goroutine uses the fields below.
+	inFlight int
+	nextSeq  uint64
+	sendSeq  uint64
+	reorder  map[uint64]parseResult
+}
+
+func newParsePool(opts ConcurrentTailOptions) *parsePool {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	maxInFlight := opts.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = workers * defaultMaxInFlightPerWorker
+	}
+
+	p := &parsePool{
+		ordered:     opts.Ordered,
+		maxInFlight: maxInFlight,
+		jobs:        make(chan parseJob, maxInFlight),
+		results:     make(chan parseResult, maxInFlight),
+		reorder:     make(map[uint64]parseResult),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *parsePool) work() {
+	defer p.wg.Done()
+	for j := range p.jobs {
+		ev, err := ParseEventLineWithOptions(j.line, j.opts)
+		p.results <- parseResult{parseJob: j, ev: ev, err: err}
+	}
+}
+
+// close stops the workers once they finish the lines in flight.
+func (p *parsePool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+// submit queues a line for parsing, first delivering results until there
+// is room for it.
+func (p *parsePool) submit(ctx context.Context, t *tailer, j parseJob) error {
+	for p.pending() >= p.maxInFlight {
+		if err := p.receive(ctx, t); err != nil {
+			return err
+		}
+	}
+	j.seq = p.nextSeq
+	p.nextSeq++
+	p.inFlight++
+	p.jobs <- j
+
+	// Deliver whatever is already parsed without waiting.
+	for {
+		select {
+		case r := <-p.results:
+			if err := p.handle(ctx, t, r); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// flush delivers every line submitted so far.
+func (p *parsePool) flush(ctx context.Context, t *tailer) error {
+	if err := p.deliverReady(ctx, t); err != nil {
+		return err
+	}
+	for p.pending() > 0 {
+		if err := p.receive(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pending returns the number of lines submitted but not yet delivered.
+func (p *parsePool) pending() int {
+	return p.inFlight + len(p.reorder)
+}
+
+func (p *parsePool) receive(ctx context.Context, t *tailer) error {
+	if p.inFlight == 0 {
+		// Only results held back by an interrupted delivery remain.
+		return p.deliverReady(ctx, t)
+	}
+	return p.handle(ctx, t, <-p.results)
+}
+
+func (p *parsePool) handle(ctx context.Context, t *tailer, r parseResult) error {
+	p.inFlight--
+	if !p.ordered {
+		return t.handleParsed(ctx, r.rl, r.line, r.ev, r.err)
+	}
+	p.reorder[r.seq] = r
+	return p.deliverReady(ctx, t)
+}
+
+// deliverReady delivers the buffered results that are next in file order.
+func (p *parsePool) deliverReady(ctx context.Context, t *tailer) error {
+	for {
+		r, ok := p.reorder[p.sendSeq]
+		if !ok {
+			return nil
+		}
+		delete(p.reorder, p.sendSeq)
+		p.sendSeq++
+		if err := t.handleParsed(ctx, r.rl, r.line, r.ev, r.err); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeJoinLog writes n timestamped join lines, with a malformed kill line
+// before every hundredth, and returns the path and the join lines.
+func writeJoinLog(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	var b strings.Builder
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if i%100 == 50 {
+			b.WriteString("K;broken\n")
+		}
+		line := fmt.Sprintf("%d:%02d J;g%d;%d;P%d", i/60, i%60, i, i%64, i)
+		lines = append(lines, line)
+		b.WriteString(line + "\n")
+	}
+	path := filepath.Join(t.TempDir(), "games_mp.log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, lines
+}
+
+func nextConcurrentEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return nil
+	}
+}
+
+// tailConcurrent tails path until n events arrive and returns them.
+func tailConcurrent(t *testing.T, path string, opts ConcurrentTailOptions, n int) []Event {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventsCh := make(chan Event)
+	done := make(chan error, 1)
+	go func() { done <- TailFileConcurrent(ctx, path, opts, eventsCh) }()
+
+	evs := make([]Event, 0, n)
+	for len(evs) < n {
+		evs = append(evs, nextConcurrentEvent(t, eventsCh))
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("TailFileConcurrent returned %v, want context.Canceled", err)
+	}
+	return evs
+}
+
+func TestTailFileConcurrentOrdered(t *testing.T) {
+	const n = 2000
+	path, lines := writeJoinLog(t, n)
+	opts := ConcurrentTailOptions{
+		TailOptions: TailOptions{TrackOffsets: true, ErrorLogInterval: time.Hour},
+		Workers:     4,
+		Ordered:     true,
+		MaxInFlight: 8,
+	}
+
+	var prevEnd int64
+	for i, e := range tailConcurrent(t, path, opts, n) {
+		b := baseOf(e)
+		if b.Raw != lines[i] {
+			t.Fatalf("event %d is %q, want %q", i, b.Raw, lines[i])
+		}
+		if b.StartOffset < prevEnd {
+			t.Fatalf("event %d starts at %d, before the previous end %d", i, b.StartOffset, prevEnd)
+		}
+		prevEnd = b.EndOffset
+	}
+}
+
+func TestTailFileConcurrentUnordered(t *testing.T) {
+	const n = 2000
+	path, lines := writeJoinLog(t, n)
+	opts := ConcurrentTailOptions{Workers: 4, TailOptions: TailOptions{ErrorLogInterval: time.Hour}}
+
+	seen := make(map[string]bool)
+	for _, e := range tailConcurrent(t, path, opts, n) {
+		raw := baseOf(e).Raw
+		if seen[raw] {
+			t.Fatalf("%q delivered twice", raw)
+		}
+		seen[raw] = true
+	}
+	for _, line := range lines {
+		if !seen[line] {
+			t.Fatalf("%q not delivered", line)
+		}
+	}
+}
+
+func TestTailFileConcurrentFollowsAppends(t *testing.T) {
+	path, _ := writeJoinLog(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventsCh := make(chan Event)
+	done := make(chan error, 1)
+	go func() {
+		done <- TailFileConcurrent(ctx, path, ConcurrentTailOptions{Ordered: true, MaxInFlight: 100}, eventsCh)
+	}()
+
+	for i := 0; i < 3; i++ {
+		nextConcurrentEvent(t, eventsCh)
+	}
+	// A line read at end of file is delivered without waiting for more.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("Q;g0;0;P0\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if e := nextConcurrentEvent(t, eventsCh); e.GetCommand() != CmdQuit {
+		t.Fatalf("got %#v, want the quit", e)
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,49 @@
+package events
+
+import "strings"
+
+// initGameJoiner buffers an InitGame line until the next line shows whether
+// it is continued. A continuation is a line that starts with the key/value
+// separator "\", which no event line does.
+type initGameJoiner struct {
+	held    rawLine
+	holding bool
+}
+
+// push returns the lines that are ready to be parsed, in order.
+func (j *initGameJoiner) push(line rawLine) []rawLine {
+	text := strings.TrimSpace(line.text)
+	if j.holding && strings.HasPrefix(text, "\\") {
+		j.held.text = strings.TrimRight(j.held.text, "\r\n") + text
+		j.held.end = line.end
+		return nil
+	}
+
+	var ready []rawLine
+	if held, ok := j.flush(); ok {
+		ready = append(ready, held)
+	}
+
+	if isInitGameLine(text) {
+		j.held = line
+		j.holding = true
+		return ready
+	}
+	return append(ready, line)
+}
+
+// flush returns the held InitGame line, if any.
+func (j *initGameJoiner) flush() (rawLine, bool) {
+	if !j.holding {
+		return rawLine{}, false
+	}
+	held := j.held
+	j.held = rawLine{}
+	j.holding = false
+	return held, true
+}
+
+func isInitGameLine(line string) bool {
+	_, rest := splitTimestamp(strings.TrimSpace(line), TimestampAuto)
+	return strings.HasPrefix(rest, CmdInitGame+":")
+}
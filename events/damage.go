@@ -0,0 +1,200 @@
+package events
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DamageEvent is a non-fatal hit ("D" lines), logged in the same layout as a
+// kill.
+type DamageEvent struct {
+	KillEvent
+}
+
+func parseDamageEvent(line string, ts *time.Duration, raw string, opts ParseOptions) (*DamageEvent, error) {
+	k, err := parseKillLine(CmdDamage, line, ts, raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DamageEvent{KillEvent: *k}, nil
+}
+
+const (
+	defaultDamageWindow     = 10 * time.Second
+	defaultDamageMaxVictims = 256
+
+	// maxHitsPerVictim bounds the hits kept for one victim; the oldest are
+	// dropped first.
+	maxHitsPerVictim = 64
+)
+
+type DamageTrackerOptions struct {
+	// Window is how long before a kill, by log timestamp, damage still
+	// counts towards it. Hits without a timestamp always count. Zero uses a
+	// default of 10 seconds.
+	Window time.Duration
+
+	// MaxVictims bounds the number of victims with damage tracked at once;
+	// beyond it the victim hit least recently is dropped. Zero uses a
+	// default of 256.
+	MaxVictims int
+}
+
+// DamageContribution is the damage one attacker dealt a victim before a
+// kill.
+type DamageContribution struct {
+	GUID   string
+	Name   string
+	Damage int
+	Hits   int
+}
+
+// DamageTracker correlates damage events with the kill that follows them, so
+// that each kill can be credited to everyone who damaged the victim, e.g. for
+// assists. A victim's damage is forgotten when they die, and everything is
+// forgotten on InitGame. It is safe for concurrent use.
+type DamageTracker struct {
+	window     time.Duration
+	maxVictims int
+
+	mu      sync.Mutex
+	victims map[string]*victimDamage
+	seq     uint64
+}
+
+type victimDamage struct {
+	hits    []damageHit
+	lastHit uint64
+}
+
+type damageHit struct {
+	guid, name string
+	damage     int
+	ts         *time.Duration
+}
+
+func NewDamageTracker() *DamageTracker {
+	return NewDamageTrackerWithOptions(DamageTrackerOptions{})
+}
+
+func NewDamageTrackerWithOptions(opts DamageTrackerOptions) *DamageTracker {
+	if opts.Window <= 0 {
+		opts.Window = defaultDamageWindow
+	}
+	if opts.MaxVictims <= 0 {
+		opts.MaxVictims = defaultDamageMaxVictims
+	}
+	return &DamageTracker{
+		window:     opts.Window,
+		maxVictims: opts.MaxVictims,
+		victims:    make(map[string]*victimDamage),
+	}
+}
+
+// ApplyEvent records damage events and, for a kill, returns the victim's
+// damage contributors within the window, most damage first. The kill's own
+// damage is not included, nor is self-inflicted or world damage. It returns
+// nil for other events.
+func (d *DamageTracker) ApplyEvent(e Event) []DamageContribution {
+	switch t := e.(type) {
+	case *ServerEvent:
+		if t.Command == CmdInitGame {
+			d.Reset()
+		}
+	case *DamageEvent:
+		d.addHit(t)
+	case *KillEvent:
+		return d.kill(t)
+	}
+	return nil
+}
+
+func (d *DamageTracker) Reset() {
+	d.mu.Lock()
+	d.victims = make(map[string]*victimDamage)
+	d.mu.Unlock()
+}
+
+func (d *DamageTracker) addHit(t *DamageEvent) {
+	if t.IsSuicide() || t.IsWorldKill() {
+		return
+	}
+	damage, err := strconv.Atoi(strings.TrimSpace(t.Damage))
+	if err != nil || damage <= 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := scoreKey(t.VictimXUID, t.VictimName)
+	v, ok := d.victims[key]
+	if !ok {
+		if len(d.victims) >= d.maxVictims {
+			d.evictLocked()
+		}
+		v = &victimDamage{}
+		d.victims[key] = v
+	}
+	d.seq++
+	v.lastHit = d.seq
+
+	if len(v.hits) >= maxHitsPerVictim {
+		v.hits = append(v.hits[:0], v.hits[1:]...)
+	}
+	v.hits = append(v.hits, damageHit{
+		guid:   t.AttackerXUID,
+		name:   t.AttackerName,
+		damage: damage,
+		ts:     t.Timestamp,
+	})
+}
+
+func (d *DamageTracker) evictLocked() {
+	var oldest string
+	var oldestSeq uint64
+	for key, v := range d.victims {
+		if oldest == "" || v.lastHit < oldestSeq {
+			oldest, oldestSeq = key, v.lastHit
+		}
+	}
+	delete(d.victims, oldest)
+}
+
+func (d *DamageTracker) kill(t *KillEvent) []DamageContribution {
+	d.mu.Lock()
+	key := scoreKey(t.VictimXUID, t.VictimName)
+	v := d.victims[key]
+	delete(d.victims, key)
+	d.mu.Unlock()
+
+	if v == nil {
+		return nil
+	}
+
+	var contribs []DamageContribution
+	index := make(map[string]int)
+	for _, h := range v.hits {
+		if h.ts != nil && t.Timestamp != nil && (*h.ts > *t.Timestamp || *t.Timestamp-*h.ts > d.window) {
+			continue
+		}
+		attacker := scoreKey(h.guid, h.name)
+		i, ok := index[attacker]
+		if !ok {
+			i = len(contribs)
+			index[attacker] = i
+			contribs = append(contribs, DamageContribution{GUID: h.guid})
+		}
+		contribs[i].Name = h.name
+		contribs[i].Damage += h.damage
+		contribs[i].Hits++
+	}
+
+	sort.SliceStable(contribs, func(i, j int) bool {
+		return contribs[i].Damage > contribs[j].Damage
+	})
+	return contribs
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// Handler handles a single event.
+type Handler interface {
+	Handle(ctx context.Context, e Event) error
+}
+
+// HandlerFunc adapts an ordinary function to a Handler.
+type HandlerFunc func(ctx context.Context, e Event) error
+
+func (f HandlerFunc) Handle(ctx context.Context, e Event) error { return f(ctx, e) }
+
+// HandlerID identifies a registration with a Dispatcher, for Off.
+type HandlerID uint64
+
+// Dispatcher routes events to the handlers registered for their command. It
+// is safe for concurrent use, including registering and removing handlers
+// from within a handler or while Run is active. Each dispatch works on the
+// handlers registered when it starts: a handler added or removed during an
+// in-flight dispatch may or may not see the current event, and sees every
+// event dispatched after On returns.
+type Dispatcher struct {
+	mu     sync.RWMutex
+	nextID HandlerID
+
+	// handlers slices are replaced, never modified in place, so a dispatch
+	// can iterate them without holding mu.
+	handlers map[string][]registeredHandler
+}
+
+type registeredHandler struct {
+	id HandlerID
+	h  Handler
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]registeredHandler)}
+}
+
+// On registers h for events whose GetCommand is command, or for every event
+// if command is empty.
+func (d *Dispatcher) On(command string, h Handler) HandlerID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	old := d.handlers[command]
+	d.handlers[command] = append(old[:len(old):len(old)], registeredHandler{id: d.nextID, h: h})
+	return d.nextID
+}
+
+func (d *Dispatcher) OnFunc(command string, f func(ctx context.Context, e Event) error) HandlerID {
+	return d.On(command, HandlerFunc(f))
+}
+
+// Off removes the handler registered for command under id. It reports
+// whether such a handler was registered.
+func (d *Dispatcher) Off(command string, id HandlerID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	old := d.handlers[command]
+	kept := make([]registeredHandler, 0, len(old))
+	for _, r := range old {
+		if r.id != id {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(old) {
+		return false
+	}
+	if len(kept) == 0 {
+		delete(d.handlers, command)
+	} else {
+		d.handlers[command] = kept
+	}
+	return true
+}
+
+// Handle calls the handlers for e's command, then those registered for every
+// event, each in registration order. All handlers run even if some fail; their
+// errors are joined. A Dispatcher is itself a Handler.
+func (d *Dispatcher) Handle(ctx context.Context, e Event) error {
+	d.mu.RLock()
+	byCommand := d.handlers[e.GetCommand()]
+	var all []registeredHandler
+	if e.GetCommand() != "" {
+		all = d.handlers[""]
+	}
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, handlers := range [][]registeredHandler{byCommand, all} {
+		for _, r := range handlers {
+			if err := r.h.Handle(ctx, e); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run dispatches the events from in until in is closed or ctx is cancelled.
+// Handler errors are logged and do not stop the loop.
+func (d *Dispatcher) Run(ctx context.Context, in <-chan Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if err := d.Handle(ctx, e); err != nil {
+				log.Printf("events: handler failed for %s event: %v", e.GetCommand(), err)
+			}
+		}
+	}
+}
+
+// Recover returns a Handler that calls h and turns a panic into a logged
+// error, so that one failing handler does not stop the events that follow.
+func Recover(h Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, e Event) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("events: handler panicked on %s event: %v\n%s", e.GetCommand(), r, debug.Stack())
+				err = fmt.Errorf("handler panicked: %v", r)
+			}
+		}()
+		return h.Handle(ctx, e)
+	})
+}
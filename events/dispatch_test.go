@@ -0,0 +1,132 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// captureLog redirects the standard logger for the rest of the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	out, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestRecoverContinuesAfterPanic(t *testing.T) {
+	logged := captureLog(t)
+
+	d := NewDispatcher()
+	var handled []string
+	d.On(CmdSay, Recover(HandlerFunc(func(ctx context.Context, e Event) error {
+		if e.(*PlayerEvent).Message == "boom" {
+			panic("bad message")
+		}
+		return nil
+	})))
+	d.OnFunc("", func(ctx context.Context, e Event) error {
+		handled = append(handled, e.(*PlayerEvent).Message)
+		return nil
+	})
+
+	in := make(chan Event, 2)
+	in <- &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, Message: "boom"}
+	in <- &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, Message: "fine"}
+	close(in)
+	if err := d.Run(context.Background(), in); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(handled, ",") != "boom,fine" {
+		t.Errorf("handled %v, want both events", handled)
+	}
+	if !strings.Contains(logged.String(), "handler panicked on say event: bad message") {
+		t.Errorf("panic not logged:\n%s", logged)
+	}
+}
+
+func TestRecoverReturnsPanicAsError(t *testing.T) {
+	captureLog(t)
+
+	h := Recover(HandlerFunc(func(context.Context, Event) error { panic("oops") }))
+	err := h.Handle(context.Background(), &BaseEvent{Command: "x"})
+	if err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("got %v, want the panic as an error", err)
+	}
+
+	ok := Recover(HandlerFunc(func(context.Context, Event) error { return nil }))
+	if err := ok.Handle(context.Background(), &BaseEvent{Command: "x"}); err != nil {
+		t.Fatalf("got %v from a handler that did not panic", err)
+	}
+}
+
+func TestDispatcherConcurrentRegistration(t *testing.T) {
+	d := NewDispatcher()
+	var calls atomic.Int64
+	count := HandlerFunc(func(context.Context, Event) error {
+		calls.Add(1)
+		return nil
+	})
+	d.On("", count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan Event)
+	runDone := make(chan error, 1)
+	go func() { runDone <- d.Run(ctx, in) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				id := d.On(CmdSay, count)
+				if err := d.Handle(context.Background(), &BaseEvent{Command: CmdSay}); err != nil {
+					t.Error(err)
+				}
+				if !d.Off(CmdSay, id) {
+					t.Error("Off did not find the handler")
+				}
+			}
+		}()
+	}
+	// A handler may register and remove handlers itself.
+	d.On(CmdQuit, HandlerFunc(func(context.Context, Event) error {
+		d.Off(CmdSay, d.On(CmdSay, count))
+		return nil
+	}))
+	for i := 0; i < 200; i++ {
+		in <- &BaseEvent{Command: CmdQuit}
+	}
+	wg.Wait()
+	close(in)
+	if err := <-runDone; err != nil {
+		t.Fatal(err)
+	}
+
+	// Every Handle saw at least the catch-all handler and its own.
+	if n := calls.Load(); n < 8*200*2+200 {
+		t.Errorf("%d handler calls, want at least %d", n, 8*200*2+200)
+	}
+
+	// Only the catch-all handler is left for say.
+	before := calls.Load()
+	if err := d.Handle(context.Background(), &BaseEvent{Command: CmdSay}); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load() - before; n != 1 {
+		t.Errorf("%d handlers ran after all were removed, want 1", n)
+	}
+}
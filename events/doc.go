@@ -0,0 +1,7 @@
+// Package events parses Plutonium T6 game server logs into typed events and
+// tails them as they are written.
+//
+// Functions that take a context and an input channel of events and return an
+// output channel, such as Filter or CoalesceChat, run on their own goroutine
+// until in is closed or ctx is cancelled, then close the output channel.
+package events
@@ -0,0 +1,67 @@
+package events
+
+import "context"
+
+// EnrichChatTeams forwards the events from in, setting SenderTeam on chat
+// events (say, sayteam and tell) to the sender's team in dir. The sender is
+// matched like the sides of ResolveKill; when it is not found, the lookup
+// fails or the directory does not know the team, SenderTeam is left as is.
+func EnrichChatTeams(ctx context.Context, in <-chan Event, dir *PlayerDirectory) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				if p := chatSender(e); p != nil {
+					dir.enrichSender(p)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func chatSender(e Event) *PlayerEvent {
+	var p *PlayerEvent
+	switch t := e.(type) {
+	case *PlayerEvent:
+		p = t
+	case *ChatEvent:
+		p = &t.PlayerEvent
+	default:
+		return nil
+	}
+	if !isChatCommand(p.Command) && p.Command != CmdTell {
+		return nil
+	}
+	return p
+}
+
+func (d *PlayerDirectory) enrichSender(p *PlayerEvent) {
+	players, err := d.lookupSnapshot()
+	if err != nil {
+		return
+	}
+
+	// Text-form chat carries only a name; Flag is then not a client number.
+	clientNum := -1
+	if p.XUID != "" {
+		clientNum = p.Flag
+	}
+	if sender := d.resolvePlayer(players, p.XUID, clientNum, p.Player); sender != nil && sender.Team != TeamUnknown {
+		p.SenderTeam = sender.Team
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// EventsEqual compares two event slices by value and returns an error
+// describing the first difference, or nil if they are equal. Events must have
+// the same concrete types; pointers, including timestamps, are compared by
+// what they point to, and nil and empty maps or slices are equal. It is meant
+// for tests of code consuming this package, e.g.
+//
+//	if err := events.EventsEqual(want, got); err != nil {
+//		t.Fatal(err)
+//	}
+func EventsEqual(want, got []Event) error {
+	for i := 0; i < len(want) && i < len(got); i++ {
+		if d := valueDiff("", reflect.ValueOf(want[i]), reflect.ValueOf(got[i])); d != "" {
+			return fmt.Errorf("event %d: %s", i, d)
+		}
+	}
+	if len(want) != len(got) {
+		return fmt.Errorf("got %d events, want %d", len(got), len(want))
+	}
+	return nil
+}
+
+// valueDiff describes how got differs from want at path, or returns "".
+func valueDiff(path string, want, got reflect.Value) string {
+	at := func(format string, args ...any) string {
+		if path == "" {
+			return fmt.Sprintf(format, args...)
+		}
+		return path + ": " + fmt.Sprintf(format, args...)
+	}
+
+	if !want.IsValid() || !got.IsValid() {
+		if want.IsValid() != got.IsValid() {
+			return at("got %v, want %v", got, want)
+		}
+		return ""
+	}
+	if want.Type() != got.Type() {
+		return at("got type %s, want %s", got.Type(), want.Type())
+	}
+
+	switch want.Kind() {
+	case reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				return at("got %v, want %v", got, want)
+			}
+			return ""
+		}
+		return valueDiff(path, want.Elem(), got.Elem())
+	case reflect.Pointer:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				return at("got %s, want %s", describe(got), describe(want))
+			}
+			return ""
+		}
+		return valueDiff(path, want.Elem(), got.Elem())
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			f := want.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			if d := valueDiff(name, want.Field(i), got.Field(i)); d != "" {
+				return d
+			}
+		}
+		return ""
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < want.Len() && i < got.Len(); i++ {
+			if d := valueDiff(fmt.Sprintf("%s[%d]", path, i), want.Index(i), got.Index(i)); d != "" {
+				return d
+			}
+		}
+		if want.Len() != got.Len() {
+			return at("got %d elements, want %d", got.Len(), want.Len())
+		}
+		return ""
+	case reflect.Map:
+		keys := make([]string, 0, want.Len()+got.Len())
+		seen := make(map[string]reflect.Value)
+		for _, m := range []reflect.Value{want, got} {
+			iter := m.MapRange()
+			for iter.Next() {
+				k := fmt.Sprint(iter.Key())
+				if _, ok := seen[k]; !ok {
+					seen[k] = iter.Key()
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := seen[k]
+			name := fmt.Sprintf("%s[%q]", path, k)
+			w, g := want.MapIndex(key), got.MapIndex(key)
+			if !w.IsValid() {
+				return name + ": unexpected entry " + describe(g)
+			}
+			if !g.IsValid() {
+				return name + ": missing entry " + describe(w)
+			}
+			if d := valueDiff(name, w, g); d != "" {
+				return d
+			}
+		}
+		return ""
+	}
+
+	if want.CanInterface() && got.CanInterface() && want.Interface() != got.Interface() {
+		return at("got %s, want %s", describe(got), describe(want))
+	}
+	return ""
+}
+
+func describe(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "nil"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprint(v)
+}
@@ -12,6 +12,16 @@ type BaseEvent struct {
 	Timestamp *time.Duration
 	Command   string
 	Raw       string
+	Source    string
+
+	// StartOffset and EndOffset are the event's byte range in the source
+	// file, set only when offset tracking is enabled.
+	StartOffset int64
+	EndOffset   int64
+
+	// SinceRoundStart is the time elapsed since the current match's InitGame,
+	// set only by AnnotateRoundTime.
+	SinceRoundStart *time.Duration
 }
 
 type PlayerEvent struct {
@@ -20,11 +30,67 @@ type PlayerEvent struct {
 	Flag    int
 	Player  string
 	Message string
+
+	// Recipient is set for private messages ("tell") to the target as it
+	// appears in the log: a name for the space form, a client number for
+	// the semicolon form.
+	Recipient string
+
+	// SenderTeam is never set by the parser; EnrichChatTeams fills it in for
+	// chat events from a PlayerDirectory. Otherwise it stays TeamUnknown.
+	SenderTeam Team
+}
+
+// JoinEvent is a player connecting ("J" lines). GUID is the network origin:
+// a GUID, a bot GUID such as "bot3", or "0".
+type JoinEvent struct {
+	BaseEvent
+	GUID      string
+	ClientNum int
+	Name      string
+
+	// Team is the trailing team field some mods append after the name, and
+	// empty when there is none.
+	Team string
+}
+
+// AsPlayerEvent returns the join in the PlayerEvent form that J lines were
+// parsed into before JoinEvent existed, with the client number in Flag.
+func (j *JoinEvent) AsPlayerEvent() *PlayerEvent {
+	return &PlayerEvent{
+		BaseEvent: j.BaseEvent,
+		XUID:      j.GUID,
+		Flag:      j.ClientNum,
+		Player:    j.Name,
+	}
+}
+
+// UnknownEvent is produced for unrecognized lines when
+// ParseOptions.UnknownEvents is set. Command holds the line's first token and
+// Line the full text after any timestamp.
+type UnknownEvent struct {
+	BaseEvent
+	Line string
+}
+
+type ChatEvent struct {
+	PlayerEvent
+	Repeat int
 }
 
 type ServerEvent struct {
 	BaseEvent
 	Data map[string]string
+
+	// FinalScores is filled in by CorrelateFinalScores on ShutdownGame.
+	FinalScores []ScoreEvent
+
+	// ExitReason is set on Exit events; the reason text is in Data["reason"].
+	ExitReason ExitReason
+
+	// Synthetic marks an InitGame replayed by the tailer from before the
+	// point it started reading (see TailOptions.SyntheticInitGame).
+	Synthetic bool
 }
 
 type KillEvent struct {
@@ -37,12 +103,70 @@ type KillEvent struct {
 	AttackerClientNum int
 	AttackerTeam      string
 	AttackerName      string
-	Weapon            string
+	Weapon            Weapon
 	Damage            string
 	MeansOfDeath      string
 	HitLocation       string
+	Distance          *float64
+
+	// ArmorDamage and ShieldDamage are the damage absorbed by armor and
+	// shields, logged by some mods after the hit location.
+	ArmorDamage  *int
+	ShieldDamage *int
+
+	// Extra holds trailing key=value fields some mods append to kill lines,
+	// e.g. "time=45". It is nil when the line has none.
+	Extra map[string]string
+
+	// RawWeapon is the weapon as logged when ParseOptions.WeaponMapper
+	// rewrote Weapon, and empty otherwise.
+	RawWeapon Weapon
 }
 
 func (b *BaseEvent) GetCommand() string           { return b.Command }
 func (b *BaseEvent) GetTimestamp() *time.Duration { return b.Timestamp }
 func (b *BaseEvent) GetRaw() string               { return b.Raw }
+func (b *BaseEvent) GetSource() string            { return b.Source }
+
+func (b *BaseEvent) base() *BaseEvent { return b }
+
+func (k *KillEvent) IsSuicide() bool {
+	if k.AttackerXUID != "" && k.AttackerXUID == k.VictimXUID {
+		return true
+	}
+	return k.AttackerClientNum >= 0 && k.AttackerClientNum == k.VictimClientNum
+}
+
+// IsFriendlyFire reports whether attacker and victim were on the same
+// playing team. Suicides, world kills and kills involving the spectator, no
+// team (free-for-all) or an unrecognized team are never friendly fire.
+func (k *KillEvent) IsFriendlyFire() bool {
+	if k.IsSuicide() || k.IsWorldKill() {
+		return false
+	}
+
+	attacker, victim := ParseTeam(k.AttackerTeam), ParseTeam(k.VictimTeam)
+	if attacker != victim {
+		return false
+	}
+	return attacker == TeamAllies || attacker == TeamAxis
+}
+
+// IsWorldKill reports whether the kill was not caused by a player, e.g. a
+// fall or a trigger hurt, which the engine logs with client number -1.
+func (k *KillEvent) IsWorldKill() bool {
+	return k.AttackerClientNum < 0 || k.AttackerTeam == "world"
+}
+
+func (k *KillEvent) CleanAttackerName() string { return CleanName(k.AttackerName) }
+func (k *KillEvent) CleanVictimName() string   { return CleanName(k.VictimName) }
+
+func (p *PlayerEvent) CleanName() string { return CleanName(p.Player) }
+func (j *JoinEvent) CleanName() string   { return CleanName(j.Name) }
+
+func baseOf(e Event) *BaseEvent {
+	if b, ok := e.(interface{ base() *BaseEvent }); ok {
+		return b.base()
+	}
+	return nil
+}
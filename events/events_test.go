@@ -0,0 +1,45 @@
+package events
+
+import "testing"
+
+func TestKillIsFriendlyFire(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"K;aa;0;axis;Alice;bb;1;axis;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", true},
+		{"K;aa;0;Allies;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", true},
+		{"K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", false},
+		{"K;aa;0;axis;Alice;aa;0;axis;Alice;frag_grenade_mp;100;MOD_GRENADE_SPLASH;none", false},
+		{"K;;-1;world;;bb;1;axis;Bob;none;100;MOD_FALLING;none", false},
+		{"K;aa;0;spectator;Alice;bb;1;spectator;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", false},
+		{"K;aa;0;marines;Alice;bb;1;marines;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", false},
+	}
+	for _, tt := range tests {
+		if got := parseKill(t, tt.line).IsFriendlyFire(); got != tt.want {
+			t.Errorf("%q: IsFriendlyFire() = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFreeForAllKillTeams(t *testing.T) {
+	// Free-for-all logs leave both team columns blank.
+	lines := []string{
+		"0:42 K;aa;0;;Alice;bb;1;;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"0:43 K;cc;2;;Carl;aa;0;;Alice;frag_grenade_mp;120;MOD_GRENADE_SPLASH;none",
+	}
+	for _, line := range lines {
+		k := parseKill(t, line)
+		if ParseTeam(k.AttackerTeam) != TeamNone || ParseTeam(k.VictimTeam) != TeamNone {
+			t.Errorf("%q: teams %q and %q, want none", line, k.AttackerTeam, k.VictimTeam)
+		}
+		if k.IsFriendlyFire() {
+			t.Errorf("%q: free-for-all kill reported as friendly fire", line)
+		}
+	}
+
+	k := parseKill(t, "K;aa;0;axis;Alice;bb;1;;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head")
+	if k.IsFriendlyFire() {
+		t.Error("kill with one blank team reported as friendly fire")
+	}
+}
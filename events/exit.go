@@ -0,0 +1,58 @@
+package events
+
+import "strings"
+
+type ExitReason int
+
+const (
+	ExitUnknown ExitReason = iota
+	ExitTimelimit
+	ExitFraglimit
+	ExitScorelimit
+	ExitCapturelimit
+	ExitRoundlimit
+	ExitVote
+	ExitIntermission
+)
+
+var exitReasonNames = map[ExitReason]string{
+	ExitUnknown:      "unknown",
+	ExitTimelimit:    "timelimit",
+	ExitFraglimit:    "fraglimit",
+	ExitScorelimit:   "scorelimit",
+	ExitCapturelimit: "capturelimit",
+	ExitRoundlimit:   "roundlimit",
+	ExitVote:         "vote",
+	ExitIntermission: "intermission",
+}
+
+func (r ExitReason) String() string {
+	if s, ok := exitReasonNames[r]; ok {
+		return s
+	}
+	return exitReasonNames[ExitUnknown]
+}
+
+// ParseExitReason maps the free-form text of an "Exit:" line, such as
+// "Timelimit hit." or "Map vote passed", to an ExitReason. Unrecognized text
+// is ExitUnknown; the text itself is kept in the event's Data["reason"].
+func ParseExitReason(s string) ExitReason {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.HasPrefix(s, "timelimit"):
+		return ExitTimelimit
+	case strings.HasPrefix(s, "fraglimit"):
+		return ExitFraglimit
+	case strings.HasPrefix(s, "scorelimit"):
+		return ExitScorelimit
+	case strings.HasPrefix(s, "capturelimit"):
+		return ExitCapturelimit
+	case strings.HasPrefix(s, "roundlimit"):
+		return ExitRoundlimit
+	case strings.Contains(s, "vote passed"):
+		return ExitVote
+	case strings.HasPrefix(s, "intermission"):
+		return ExitIntermission
+	}
+	return ExitUnknown
+}
@@ -0,0 +1,48 @@
+package events
+
+import "testing"
+
+func TestParseExitLines(t *testing.T) {
+	tests := []struct {
+		line   string
+		reason ExitReason
+		text   string
+	}{
+		{"Exit: Timelimit hit.", ExitTimelimit, "Timelimit hit."},
+		{"12:34 Exit: Fraglimit hit.", ExitFraglimit, "Fraglimit hit."},
+		{"Exit: scorelimit hit", ExitScorelimit, "scorelimit hit"},
+		{"Exit: Capturelimit hit.", ExitCapturelimit, "Capturelimit hit."},
+		{"Exit: Roundlimit hit.", ExitRoundlimit, "Roundlimit hit."},
+		{"Exit: Map vote passed", ExitVote, "Map vote passed"},
+		{"Exit: Intermission ended.", ExitIntermission, "Intermission ended."},
+		{"Exit: server admin", ExitUnknown, "server admin"},
+		{"Exit:", ExitUnknown, ""},
+	}
+	for _, tt := range tests {
+		e, err := ParseEventLine(tt.line)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.line, err)
+		}
+		s, ok := e.(*ServerEvent)
+		if !ok || s.Command != CmdExit {
+			t.Fatalf("%q parsed as %#v", tt.line, e)
+		}
+		if s.ExitReason != tt.reason || s.Data["reason"] != tt.text {
+			t.Errorf("%q: reason %v %q, want %v %q", tt.line, s.ExitReason, s.Data["reason"], tt.reason, tt.text)
+		}
+	}
+}
+
+func TestExitReasonString(t *testing.T) {
+	for r, name := range exitReasonNames {
+		if r.String() != name {
+			t.Errorf("%d.String() = %q, want %q", r, r.String(), name)
+		}
+		if r != ExitUnknown && ParseExitReason(name) != r && r != ExitVote {
+			t.Errorf("ParseExitReason(%q) = %v, want %v", name, ParseExitReason(name), r)
+		}
+	}
+	if got := ExitReason(99).String(); got != "unknown" {
+		t.Errorf("ExitReason(99).String() = %q", got)
+	}
+}
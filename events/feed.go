@@ -0,0 +1,35 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+)
+
+// FeedFile is like FeedFileWithOptions with default options.
+func FeedFile(ctx context.Context, path string, eventsCh chan<- Event) error {
+	return FeedFileWithOptions(ctx, path, TailOptions{}, eventsCh)
+}
+
+// FeedFileWithOptions reads the file at path once, from the start, sending
+// its events on eventsCh as fast as they are consumed, and closes eventsCh
+// when done, whether at end of file, on error or on cancellation. Unlike the
+// tailers it neither polls nor follows rotation. Parse errors are handled as
+// by the tailer; options that concern following a file are ignored.
+func FeedFileWithOptions(ctx context.Context, path string, opts TailOptions, eventsCh chan<- Event) error {
+	defer close(eventsCh)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if st, err := f.Stat(); err == nil && st.IsDir() {
+		return fmt.Errorf("%w: %s", ErrNotAFile, path)
+	}
+
+	br := bufio.NewReader(f)
+	return TailFuncWithOptions(ctx, func() (string, error) { return br.ReadString('\n') }, opts, eventsCh)
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"strings"
+)
+
+type playerRef struct {
+	GUID      string
+	ClientNum int
+	Name      string
+}
+
+// Filter forwards the events from in for which keep returns true.
+func Filter(ctx context.Context, in <-chan Event, keep func(Event) bool) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				if !keep(e) {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// ByPlayer keeps the events attributable to the player with the given GUID:
+// kills and damage where they are attacker or victim, and their join, quit,
+// rename, chat, voice, spawn, objective, weapon and item events. Votes, scores
+// and awards carry only a client number, so they are matched by ByClientNum
+// but dropped here, as are text-form chat lines, which carry no GUID. Server
+// events, server messages and unrecognized lines have no subject and are
+// always dropped.
+func ByPlayer(ctx context.Context, in <-chan Event, guid string) <-chan Event {
+	guid = strings.TrimSpace(guid)
+	return Filter(ctx, in, func(e Event) bool {
+		for _, ref := range subjects(e) {
+			if ref.GUID != "" && strings.EqualFold(ref.GUID, guid) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ByClientNum is like ByPlayer but matches on client number. Client numbers
+// are reused once a player disconnects, so the stream may cover several
+// players over time.
+func ByClientNum(ctx context.Context, in <-chan Event, clientNum int) <-chan Event {
+	return Filter(ctx, in, func(e Event) bool {
+		for _, ref := range subjects(e) {
+			if ref.ClientNum >= 0 && ref.ClientNum == clientNum {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func subjects(e Event) []playerRef {
+	switch t := e.(type) {
+	case *PlayerEvent:
+		return []playerRef{playerEventRef(t)}
+	case *ChatEvent:
+		return []playerRef{playerEventRef(&t.PlayerEvent)}
+	case *JoinEvent:
+		return []playerRef{{GUID: t.GUID, ClientNum: t.ClientNum, Name: t.Name}}
+	case *NameChangeEvent:
+		return []playerRef{{GUID: t.GUID, ClientNum: t.ClientNum, Name: t.NewName}}
+	case *KillEvent:
+		return []playerRef{
+			{GUID: t.AttackerXUID, ClientNum: t.AttackerClientNum, Name: t.AttackerName},
+			{GUID: t.VictimXUID, ClientNum: t.VictimClientNum, Name: t.VictimName},
+		}
+	case *DamageEvent:
+		return []playerRef{
+			{GUID: t.AttackerXUID, ClientNum: t.AttackerClientNum, Name: t.AttackerName},
+			{GUID: t.VictimXUID, ClientNum: t.VictimClientNum, Name: t.VictimName},
+		}
+	case *ObjectiveEvent:
+		if t.ClientNum < 0 {
+			return nil
+		}
+		return []playerRef{{GUID: t.XUID, ClientNum: t.ClientNum, Name: t.Player}}
+	case *WeaponChangeEvent:
+		return []playerRef{{GUID: t.XUID, ClientNum: t.ClientNum, Name: t.Player}}
+	case *ItemEvent:
+		return []playerRef{{GUID: t.XUID, ClientNum: t.ClientNum, Name: t.Player}}
+	case *VoiceEvent:
+		if t.XUID == "" {
+			return []playerRef{{ClientNum: -1, Name: t.Player}}
+		}
+		return []playerRef{{GUID: t.XUID, ClientNum: t.ClientNum, Name: t.Player}}
+	case *AwardEvent:
+		return []playerRef{{ClientNum: t.ClientNum}}
+	case *VoteEvent:
+		return []playerRef{{ClientNum: t.ClientNum}}
+	case *ScoreEvent:
+		return []playerRef{{ClientNum: t.ClientNum, Name: t.Player}}
+	case *SpawnEvent:
+		return []playerRef{{GUID: t.XUID, ClientNum: t.ClientNum, Name: t.Player}}
+	}
+	return nil
+}
+
+func playerEventRef(p *PlayerEvent) playerRef {
+	// Text-form chat lines ("say <name> <msg>") carry neither GUID nor
+	// client number; Flag is left at zero by the parser.
+	if p.XUID == "" {
+		return playerRef{ClientNum: -1, Name: p.Player}
+	}
+	return playerRef{GUID: p.XUID, ClientNum: p.Flag, Name: p.Player}
+}
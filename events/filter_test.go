@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFilterSubjects(t *testing.T) {
+	base := BaseEvent{Command: "x"}
+	kill := KillEvent{
+		BaseEvent:    base,
+		AttackerXUID: "bb", AttackerClientNum: 5, AttackerName: "Bob",
+		VictimXUID: "AA", VictimClientNum: 3, VictimName: "Alice",
+	}
+	tests := []struct {
+		e               Event
+		byPlayer, byNum bool
+	}{
+		{&BaseEvent{Command: "x"}, false, false},
+		{&PlayerEvent{BaseEvent: base, XUID: "aa", Flag: 3, Player: "Alice"}, true, true},
+		// A text-form chat line carries neither GUID nor client number.
+		{&PlayerEvent{BaseEvent: base, Flag: 3, Player: "Alice"}, false, false},
+		{&JoinEvent{BaseEvent: base, GUID: "aa", ClientNum: 3, Name: "Alice"}, true, true},
+		{&NameChangeEvent{BaseEvent: base, GUID: "aa", ClientNum: 3, NewName: "Alicia"}, true, true},
+		{&VoiceEvent{BaseEvent: base, XUID: "aa", ClientNum: 3, Player: "Alice"}, true, true},
+		{&SpawnEvent{BaseEvent: base, XUID: "aa", ClientNum: 3, Player: "Alice"}, true, true},
+		{&ServerEvent{BaseEvent: base}, false, false},
+		{&kill, true, true},
+		{&DamageEvent{KillEvent: kill}, true, true},
+		{&ChatEvent{PlayerEvent: PlayerEvent{BaseEvent: base, XUID: "aa", Flag: 3}, Repeat: 2}, true, true},
+		{&ObjectiveEvent{BaseEvent: base, XUID: "aa", ClientNum: 3}, true, true},
+		{&WeaponChangeEvent{BaseEvent: base, XUID: "aa", ClientNum: 3}, true, true},
+		{&ItemEvent{BaseEvent: base, XUID: "aa", ClientNum: 3}, true, true},
+		{&VoteEvent{BaseEvent: base, ClientNum: 3}, false, true},
+		{&UnknownEvent{BaseEvent: base, Line: "x"}, false, false},
+		{&ScoreEvent{BaseEvent: base, ClientNum: 3, Player: "Alice"}, false, true},
+		{&AwardEvent{BaseEvent: base, ClientNum: 3}, false, true},
+		{&ServerMessageEvent{BaseEvent: base, Message: "Alice wins"}, false, false},
+	}
+
+	// Every event type that can be marshaled must appear above.
+	covered := make(map[reflect.Type]bool)
+	for _, tt := range tests {
+		covered[reflect.TypeOf(tt.e)] = true
+	}
+	for name, newEvent := range jsonEventTypes {
+		if !covered[reflect.TypeOf(newEvent())] {
+			t.Errorf("event type %q (%T) not covered", name, newEvent())
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tt := range tests {
+		if got := len(collect(ByPlayer(ctx, feed(tt.e), "aa"))) == 1; got != tt.byPlayer {
+			t.Errorf("ByPlayer kept %T: %v, want %v", tt.e, got, tt.byPlayer)
+		}
+		if got := len(collect(ByClientNum(ctx, feed(tt.e), 3))) == 1; got != tt.byNum {
+			t.Errorf("ByClientNum kept %T: %v, want %v", tt.e, got, tt.byNum)
+		}
+		if got := len(collect(ByClientNum(ctx, feed(tt.e), 0))) == 1; got {
+			t.Errorf("ByClientNum(0) kept %T", tt.e)
+		}
+	}
+}
+
+// feed returns a closed channel holding evs.
+func feed(evs ...Event) <-chan Event {
+	ch := make(chan Event, len(evs))
+	for _, e := range evs {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
+func collect(ch <-chan Event) []Event {
+	var evs []Event
+	for e := range ch {
+		evs = append(evs, e)
+	}
+	return evs
+}
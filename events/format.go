@@ -0,0 +1,182 @@
+package events
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFormattable is returned by FormatEventLine for events it cannot turn
+// back into a log line.
+var ErrNotFormattable = errors.New("event cannot be formatted as a log line")
+
+// FormatEventLine formats e as a log line, without a terminator, from its
+// fields rather than its Raw text, so changes made to the event, such as a
+// redacted chat message, are reflected. Joins, player and chat lines, kills,
+// damage, InitGame, ShutdownGame and Exit are supported; other events yield
+// an error wrapping ErrNotFormattable. The timestamp, if any, is written as
+// "M:SS". InitGame keys are written sorted.
+func FormatEventLine(e Event) (string, error) {
+	var line string
+	switch t := e.(type) {
+	case *JoinEvent:
+		line = strings.Join([]string{CmdJoin, t.GUID, strconv.Itoa(t.ClientNum), t.Name}, ";")
+		if t.Team != "" {
+			line += ";" + t.Team
+		}
+	case *PlayerEvent:
+		line = formatPlayerEvent(t)
+	case *KillEvent:
+		line = formatKillEvent(t)
+	case *DamageEvent:
+		line = formatKillEvent(&t.KillEvent)
+	case *ServerEvent:
+		switch t.Command {
+		case CmdInitGame:
+			keys := make([]string, 0, len(t.Data))
+			for k := range t.Data {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var b strings.Builder
+			b.WriteString(CmdInitGame + ": ")
+			for _, k := range keys {
+				b.WriteString("\\" + k + "\\" + t.Data[k])
+			}
+			line = b.String()
+		case CmdShutdownGame:
+			line = CmdShutdownGame + ":"
+		case CmdExit:
+			line = CmdExit + ": " + t.Data["reason"]
+		}
+	}
+	if line == "" {
+		return "", fmt.Errorf("%w: %T", ErrNotFormattable, e)
+	}
+
+	if ts := e.GetTimestamp(); ts != nil {
+		line = formatTimestamp(*ts) + " " + line
+	}
+	return line, nil
+}
+
+func formatPlayerEvent(p *PlayerEvent) string {
+	if p.XUID == "" {
+		// The text forms, which carry no GUID or client number.
+		switch p.Command {
+		case CmdSay, CmdSayTeam:
+			return strings.TrimSpace(p.Command + " " + p.Player + " " + p.Message)
+		case CmdTell:
+			return strings.TrimSpace(p.Command + " " + p.Player + " " + p.Recipient + " " + p.Message)
+		}
+	}
+
+	fields := []string{p.Command, p.XUID, strconv.Itoa(p.Flag), p.Player}
+	switch p.Command {
+	case CmdTell:
+		fields = append(fields, p.Recipient, p.Message)
+	case CmdSay, CmdSayTeam:
+		fields = append(fields, p.Message)
+	case CmdJoin, CmdQuit:
+	default:
+		if p.Message != "" {
+			fields = append(fields, p.Message)
+		}
+	}
+	return strings.Join(fields, ";")
+}
+
+func formatKillEvent(k *KillEvent) string {
+	fields := []string{
+		k.Command,
+		k.AttackerXUID, strconv.Itoa(k.AttackerClientNum), k.AttackerTeam, k.AttackerName,
+		k.VictimXUID, strconv.Itoa(k.VictimClientNum), k.VictimTeam, k.VictimName,
+		string(k.Weapon), k.Damage, k.MeansOfDeath, k.HitLocation,
+	}
+	if k.Distance != nil {
+		fields = append(fields, strconv.FormatFloat(*k.Distance, 'f', -1, 64))
+	}
+	if k.ArmorDamage != nil || k.ShieldDamage != nil {
+		fields = append(fields, formatOptionalInt(k.ArmorDamage), formatOptionalInt(k.ShieldDamage))
+	}
+
+	keys := make([]string, 0, len(k.Extra))
+	for key := range k.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, key+"="+k.Extra[key])
+	}
+	return strings.Join(fields, ";")
+}
+
+func formatOptionalInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func formatTimestamp(ts time.Duration) string {
+	sec := int(ts / time.Second)
+	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
+}
+
+// writeFlushEvery bounds the events WriteEvents buffers while more are
+// waiting on the channel.
+const writeFlushEvery = 256
+
+type WriteOptions struct {
+	// Strict makes WriteEvents fail on events FormatEventLine cannot format.
+	// By default their Raw line is written instead, and only events without
+	// one fail.
+	Strict bool
+}
+
+// WriteEvents is like WriteEventsWithOptions with default options.
+func WriteEvents(w io.Writer, in <-chan Event) error {
+	return WriteEventsWithOptions(w, in, WriteOptions{})
+}
+
+// WriteEventsWithOptions writes the events from in to w as log lines, one
+// per line, until in is closed, e.g. to write a filtered or redacted copy of
+// a log. Events are formatted with FormatEventLine. Output is buffered and
+// flushed whenever no further event is waiting, every 256 events, and at the
+// end. It returns the first formatting or write error; the remaining events
+// are then not read.
+func WriteEventsWithOptions(w io.Writer, in <-chan Event, opts WriteOptions) error {
+	bw, ok := w.(*bufio.Writer)
+	if !ok {
+		bw = bufio.NewWriter(w)
+	}
+
+	pending := 0
+	for e := range in {
+		line, err := FormatEventLine(e)
+		if err != nil {
+			if opts.Strict || !errors.Is(err, ErrNotFormattable) || e.GetRaw() == "" {
+				bw.Flush()
+				return err
+			}
+			line = e.GetRaw()
+		}
+		if _, err := bw.WriteString(line + "\n"); err != nil {
+			return err
+		}
+
+		pending++
+		if len(in) == 0 || pending >= writeFlushEvery {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			pending = 0
+		}
+	}
+	return bw.Flush()
+}
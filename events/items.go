@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type WeaponChangeEvent struct {
+	BaseEvent
+	XUID      string
+	ClientNum int
+	Player    string
+	Weapon    Weapon
+}
+
+type ItemEvent struct {
+	BaseEvent
+	XUID      string
+	ClientNum int
+	Player    string
+	Item      string
+}
+
+// parseItemEvent handles "Weapon;guid;num[;name];weapon" and
+// "ItemPickup;guid;num[;name];item" lines. The item token is kept verbatim.
+func parseItemEvent(line string, ts *time.Duration, raw string, numBase int) (Event, error) {
+	parts := strings.SplitN(line, ";", 4)
+	if parts[0] != CmdWeapon && parts[0] != CmdItemPickup {
+		return nil, fmt.Errorf("not an item event")
+	}
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("invalid item event line: %q", line)
+	}
+
+	clientNum, err := parseClientNum(parts[2], numBase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", parts[2], err)
+	}
+
+	// The item is the last field, so a name may contain ';'.
+	name, item := "", parts[3]
+	if i := strings.LastIndexByte(item, ';'); i >= 0 {
+		name, item = item[:i], item[i+1:]
+	}
+
+	base := BaseEvent{
+		Timestamp: ts,
+		Command:   parts[0],
+		Raw:       raw,
+	}
+
+	if parts[0] == CmdWeapon {
+		return &WeaponChangeEvent{
+			BaseEvent: base,
+			XUID:      parts[1],
+			ClientNum: clientNum,
+			Player:    name,
+			Weapon:    Weapon(item),
+		}, nil
+	}
+	return &ItemEvent{
+		BaseEvent: base,
+		XUID:      parts[1],
+		ClientNum: clientNum,
+		Player:    name,
+		Item:      item,
+	}, nil
+}
+
+// FillKillWeapons forwards the events from in, filling the Weapon of kill
+// events that log none with the weapon the attacker last changed to, as seen
+// in the WeaponChangeEvents before it. The kill events are modified in place,
+// so a consumer sharing them with other readers of in sees the filled weapon
+// there too. A held weapon is forgotten on InitGame and when its client joins
+// or quits, and is only used when the GUIDs of the change and the kill agree.
+// Changes by client numbers outside the engine's range are ignored.
+func FillKillWeapons(ctx context.Context, in <-chan Event) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		held := make(map[int]*WeaponChangeEvent)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				switch t := e.(type) {
+				case *WeaponChangeEvent:
+					if validClientNum(t.ClientNum) {
+						held[t.ClientNum] = t
+					}
+				case *KillEvent:
+					if w := held[t.AttackerClientNum]; t.Weapon == "" && w != nil && (w.XUID == "" || w.XUID == t.AttackerXUID) {
+						t.Weapon = w.Weapon
+					}
+				case *JoinEvent:
+					delete(held, t.ClientNum)
+				case *PlayerEvent:
+					if t.Command == CmdQuit {
+						delete(held, t.Flag)
+					}
+				case *ServerEvent:
+					if t.Command == CmdInitGame {
+						held = make(map[int]*WeaponChangeEvent)
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseItemEvent(t *testing.T) {
+	tests := []struct {
+		line, player, item string
+	}{
+		{"ItemPickup;aa;0;health", "", "health"},
+		{"ItemPickup;aa;0;Alice;health", "Alice", "health"},
+		{"ItemPickup;aa;0;Al;ice;health", "Al;ice", "health"},
+		{"ItemPickup;aa;0;Alice;", "Alice", ""},
+	}
+	for _, tt := range tests {
+		e, err := ParseEventLine(tt.line)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.line, err)
+		}
+		ev, ok := e.(*ItemEvent)
+		if !ok {
+			t.Fatalf("%q parsed as %T", tt.line, e)
+		}
+		if ev.Player != tt.player || ev.Item != tt.item || ev.XUID != "aa" {
+			t.Errorf("%q: got player %q item %q, want %q %q", tt.line, ev.Player, ev.Item, tt.player, tt.item)
+		}
+	}
+
+	e, err := ParseEventLine("Weapon;aa;3;A;B;dsr50_mp+silencer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, ok := e.(*WeaponChangeEvent); !ok || w.Player != "A;B" || w.Weapon != "dsr50_mp+silencer" || w.ClientNum != 3 {
+		t.Errorf("got %#v", e)
+	}
+}
+
+func TestFillKillWeapons(t *testing.T) {
+	lines := []string{
+		"Weapon;bb;1;Bob;ak47_mp",
+		"K;bb;1;allies;Bob;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head",
+		"K;bb;1;allies;Bob;aa;0;axis;Alice;m4_mp;100;MOD_RIFLE_BULLET;head",
+		"K;cc;1;allies;Carl;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head",
+		"Q;bb;1;Bob",
+		"K;bb;1;allies;Bob;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head",
+		"Weapon;bb;1;Bob;ak47_mp",
+		"InitGame: \\g_gametype\\dm",
+		"K;bb;1;allies;Bob;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head",
+	}
+	want := []Weapon{"ak47_mp", "m4_mp", "", "", ""}
+
+	in := make(chan Event, len(lines))
+	for _, line := range lines {
+		e, err := ParseEventLine(line)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		in <- e
+	}
+	close(in)
+
+	var got []Weapon
+	for e := range FillKillWeapons(context.Background(), in) {
+		if k, ok := e.(*KillEvent); ok {
+			got = append(got, k.Weapon)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d kills, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("kill %d: weapon %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFillKillWeaponsIgnoresOutOfRangeClients(t *testing.T) {
+	change := &WeaponChangeEvent{BaseEvent: BaseEvent{Command: CmdWeapon}, XUID: "bb", ClientNum: 1000, Weapon: "ak47_mp"}
+	kill := parseKill(t, "K;bb;1000;allies;Bob;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head")
+	collect(FillKillWeapons(context.Background(), feed(change, kill)))
+	if kill.Weapon != "" {
+		t.Errorf("weapon %q filled from an out-of-range client", kill.Weapon)
+	}
+
+	// Kills are filled in place.
+	change.ClientNum = 1
+	kill = parseKill(t, "K;bb;1;allies;Bob;aa;0;axis;Alice;;100;MOD_RIFLE_BULLET;head")
+	collect(FillKillWeapons(context.Background(), feed(change, kill)))
+	if kill.Weapon != "ak47_mp" {
+		t.Errorf("kill weapon = %q, want it filled in place", kill.Weapon)
+	}
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var jsonEventTypes = map[string]func() Event{
+	"base":      func() Event { return &BaseEvent{} },
+	"player":    func() Event { return &PlayerEvent{} },
+	"join":      func() Event { return &JoinEvent{} },
+	"rename":    func() Event { return &NameChangeEvent{} },
+	"voice":     func() Event { return &VoiceEvent{} },
+	"spawn":     func() Event { return &SpawnEvent{} },
+	"server":    func() Event { return &ServerEvent{} },
+	"kill":      func() Event { return &KillEvent{} },
+	"damage":    func() Event { return &DamageEvent{} },
+	"chat":      func() Event { return &ChatEvent{} },
+	"objective": func() Event { return &ObjectiveEvent{} },
+	"weapon":    func() Event { return &WeaponChangeEvent{} },
+	"item":      func() Event { return &ItemEvent{} },
+	"vote":      func() Event { return &VoteEvent{} },
+	"unknown":   func() Event { return &UnknownEvent{} },
+	"score":     func() Event { return &ScoreEvent{} },
+	"award":     func() Event { return &AwardEvent{} },
+	"message":   func() Event { return &ServerMessageEvent{} },
+}
+
+type jsonEnvelope struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+func MarshalEventJSON(e Event) ([]byte, error) {
+	name, ok := jsonTypeName(e)
+	if !ok {
+		return nil, fmt.Errorf("unsupported event type %T", e)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonEnvelope{Type: name, Event: data})
+}
+
+func UnmarshalEventJSON(data []byte) (Event, error) {
+	var env jsonEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	newEvent, ok := jsonEventTypes[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", env.Type)
+	}
+
+	e := newEvent()
+	if err := json.Unmarshal(env.Event, e); err != nil {
+		return nil, fmt.Errorf("invalid %s event: %w", env.Type, err)
+	}
+	return e, nil
+}
+
+// jsonlErrorBuffer is the capacity of the error channel returned by
+// ReadJSONL.
+const jsonlErrorBuffer = 64
+
+// ReadJSONL streams events from JSON lines produced by MarshalEventJSON.
+// Malformed lines are skipped and reported on the error channel, which is
+// buffered so that reading it only after the event channel is closed never
+// blocks the reader. Once the buffer is full further line errors are dropped
+// and counted, and the count is reported by a last error. Both channels are
+// closed once r is exhausted; the event channel must be drained, or r closed
+// to stop the reader early.
+func ReadJSONL(r io.Reader) (<-chan Event, <-chan error) {
+	eventsCh := make(chan Event)
+	errCh := make(chan error, jsonlErrorBuffer)
+
+	go func() {
+		defer close(errCh)
+		defer close(eventsCh)
+
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		lineNum, dropped := 0, 0
+		for sc.Scan() {
+			lineNum++
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+
+			e, err := UnmarshalEventJSON([]byte(line))
+			if err != nil {
+				// The last slot is kept for the final error below.
+				if len(errCh) < cap(errCh)-1 {
+					errCh <- fmt.Errorf("line %d: %w", lineNum, err)
+				} else {
+					dropped++
+				}
+				continue
+			}
+			eventsCh <- e
+		}
+
+		var errs []error
+		if dropped > 0 {
+			errs = append(errs, fmt.Errorf("%d more malformed lines not reported", dropped))
+		}
+		if err := sc.Err(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := errors.Join(errs...); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return eventsCh, errCh
+}
+
+func jsonTypeName(e Event) (string, bool) {
+	t := reflect.TypeOf(e)
+	for name, newEvent := range jsonEventTypes {
+		if reflect.TypeOf(newEvent()) == t {
+			return name, true
+		}
+	}
+	return "", false
+}
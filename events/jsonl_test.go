@@ -0,0 +1,65 @@
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadJSONLRoundTrip(t *testing.T) {
+	want := []Event{
+		parseKill(t, killLine),
+		mustParse(t, "J;aa;3;Alice;axis", ParseOptions{}),
+		mustParse(t, "12:34 say;aa;3;Alice;hello", ParseOptions{}),
+		mustParse(t, "InitGame: \\g_gametype\\dm\\mapname\\mp_crash", ParseOptions{}),
+	}
+
+	var buf bytes.Buffer
+	for i, e := range want {
+		data, err := MarshalEventJSON(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+		if i == 1 {
+			buf.WriteString("{not json\n\n")
+		}
+	}
+
+	eventsCh, errCh := ReadJSONL(&buf)
+	var got []Event
+	for e := range eventsCh {
+		got = append(got, e)
+	}
+	if err := EventsEqual(want, got); err != nil {
+		t.Error(err)
+	}
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "line 3:") {
+		t.Errorf("errors = %v, want one for line 3", errs)
+	}
+}
+
+func TestReadJSONLDropsErrorsBeyondBuffer(t *testing.T) {
+	const bad = jsonlErrorBuffer + 10
+	eventsCh, errCh := ReadJSONL(strings.NewReader(strings.Repeat("bad\n", bad)))
+	for range eventsCh {
+	}
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	if len(errs) != jsonlErrorBuffer {
+		t.Fatalf("got %d errors, want %d", len(errs), jsonlErrorBuffer)
+	}
+	last := errs[len(errs)-1].Error()
+	if want := "11 more malformed lines not reported"; last != want {
+		t.Errorf("last error = %q, want %q", last, want)
+	}
+}
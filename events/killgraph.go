@@ -0,0 +1,102 @@
+package events
+
+// KillGraph holds who killed whom in a set of kills, as a directed multigraph
+// from attacker to victim. Players are identified by GUID, or by name when
+// the GUID is unknown; the query methods accept either. Suicides and world
+// kills are left out.
+type KillGraph struct {
+	kills  map[string]map[string]int
+	ids    map[string]string
+	byName map[string]string
+}
+
+func NewKillGraph(kills []*KillEvent) *KillGraph {
+	g := &KillGraph{
+		kills:  make(map[string]map[string]int),
+		ids:    make(map[string]string),
+		byName: make(map[string]string),
+	}
+	for _, k := range kills {
+		if k == nil || k.IsSuicide() || k.IsWorldKill() {
+			continue
+		}
+		attacker := g.add(k.AttackerXUID, k.AttackerName)
+		victim := g.add(k.VictimXUID, k.VictimName)
+		if g.kills[attacker] == nil {
+			g.kills[attacker] = make(map[string]int)
+		}
+		g.kills[attacker][victim]++
+	}
+	return g
+}
+
+// add records a player and returns their key.
+func (g *KillGraph) add(guid, name string) string {
+	key := scoreKey(guid, name)
+	id := guid
+	if id == "" {
+		id = name
+	}
+	g.ids[key] = id
+	if name != "" {
+		g.byName[name] = key
+	}
+	return key
+}
+
+// key resolves a GUID or name to a player's key.
+func (g *KillGraph) key(player string) (string, bool) {
+	if _, ok := g.ids[player]; ok {
+		return player, true
+	}
+	key, ok := g.byName[player]
+	return key, ok
+}
+
+// KillsBetween returns how many times attacker killed victim.
+func (g *KillGraph) KillsBetween(attacker, victim string) int {
+	a, ok := g.key(attacker)
+	if !ok {
+		return 0
+	}
+	v, ok := g.key(victim)
+	if !ok {
+		return 0
+	}
+	return g.kills[a][v]
+}
+
+// Nemesis returns the player who killed player most often and how often, or
+// "" and 0 if nobody did. Ties go to the lowest GUID or name.
+func (g *KillGraph) Nemesis(player string) (string, int) {
+	v, ok := g.key(player)
+	if !ok {
+		return "", 0
+	}
+	var best string
+	var most int
+	for attacker, victims := range g.kills {
+		n := victims[v]
+		if id := g.ids[attacker]; n > most || n == most && n > 0 && id < best {
+			best, most = id, n
+		}
+	}
+	return best, most
+}
+
+// Victim returns the player whom player killed most often and how often, or
+// "" and 0 if they killed nobody. Ties go to the lowest GUID or name.
+func (g *KillGraph) Victim(player string) (string, int) {
+	a, ok := g.key(player)
+	if !ok {
+		return "", 0
+	}
+	var best string
+	var most int
+	for victim, n := range g.kills[a] {
+		if id := g.ids[victim]; n > most || n == most && id < best {
+			best, most = id, n
+		}
+	}
+	return best, most
+}
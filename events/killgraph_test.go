@@ -0,0 +1,65 @@
+package events
+
+import "testing"
+
+func graphKill(attGUID string, attNum int, attName, vicGUID string, vicNum int, vicName string) *KillEvent {
+	return &KillEvent{
+		AttackerXUID: attGUID, AttackerClientNum: attNum, AttackerName: attName, AttackerTeam: "axis",
+		VictimXUID: vicGUID, VictimClientNum: vicNum, VictimName: vicName, VictimTeam: "allies",
+	}
+}
+
+func TestKillGraph(t *testing.T) {
+	g := NewKillGraph([]*KillEvent{
+		graphKill("aa", 0, "Alice", "bb", 1, "Bob"),
+		graphKill("aa", 0, "Alice", "bb", 1, "Bob"),
+		graphKill("aa", 0, "Alice", "", 2, "Carl"),
+		graphKill("bb", 1, "Bob", "aa", 0, "Alice"),
+		graphKill("", 2, "Carl", "bb", 1, "Bob"),
+		graphKill("", 2, "Carl", "bb", 1, "Bob"),
+		graphKill("aa", 0, "Alice", "aa", 0, "Alice"),
+		graphKill("", -1, "", "aa", 0, "Alice"),
+		nil,
+	})
+
+	tests := []struct {
+		attacker, victim string
+		want             int
+	}{
+		{"aa", "bb", 2},
+		{"Alice", "Bob", 2},
+		{"aa", "Carl", 1},
+		{"Carl", "bb", 2},
+		{"bb", "aa", 1},
+		{"aa", "aa", 0},
+		{"bb", "Carl", 0},
+		{"nobody", "bb", 0},
+	}
+	for _, tt := range tests {
+		if got := g.KillsBetween(tt.attacker, tt.victim); got != tt.want {
+			t.Errorf("KillsBetween(%q, %q) = %d, want %d", tt.attacker, tt.victim, got, tt.want)
+		}
+	}
+
+	// Alice and Carl both killed Bob twice; the tie goes to the lower id.
+	if who, n := g.Nemesis("Bob"); who != "Carl" || n != 2 {
+		t.Errorf("Nemesis(Bob) = %q, %d; want Carl, 2", who, n)
+	}
+	if who, n := g.Nemesis("aa"); who != "bb" || n != 1 {
+		t.Errorf("Nemesis(aa) = %q, %d; want bb, 1", who, n)
+	}
+	if who, n := g.Victim("Alice"); who != "bb" || n != 2 {
+		t.Errorf("Victim(Alice) = %q, %d; want bb, 2", who, n)
+	}
+	if who, n := g.Nemesis("nobody"); who != "" || n != 0 {
+		t.Errorf("Nemesis(nobody) = %q, %d", who, n)
+	}
+	if who, n := g.Victim("Bob"); who != "aa" || n != 1 {
+		t.Errorf("Victim(Bob) = %q, %d; want aa, 1", who, n)
+	}
+
+	empty := NewKillGraph(nil)
+	if who, n := empty.Victim("aa"); who != "" || n != 0 {
+		t.Errorf("empty graph: Victim = %q, %d", who, n)
+	}
+}
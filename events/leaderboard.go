@@ -0,0 +1,122 @@
+package events
+
+import "sync"
+
+const defaultLeaderboardMaxPlayers = 1024
+
+type LeaderboardOptions struct {
+	// MaxPlayers bounds the number of players tracked per match; beyond it
+	// the lowest-ranked player is dropped. Zero uses a default of 1024.
+	MaxPlayers int
+
+	// ExcludeBots leaves out players for which IsBot reports true. Kills
+	// between a bot and a human still count for the human. Bots are
+	// included by default.
+	ExcludeBots bool
+}
+
+// Leaderboard keeps a live ranking of players by kills as kill events are
+// applied, in the same order as MatchSummary.Scores: most kills first, then
+// fewest deaths, then name. Each kill moves the affected players only as far
+// as their new rank, so reads never sort. It resets on InitGame and is safe
+// for concurrent use.
+type Leaderboard struct {
+	maxPlayers  int
+	excludeBots bool
+
+	mu    sync.RWMutex
+	ranks []*PlayerScore
+	index map[string]int
+}
+
+func NewLeaderboard() *Leaderboard {
+	return NewLeaderboardWithOptions(LeaderboardOptions{})
+}
+
+func NewLeaderboardWithOptions(opts LeaderboardOptions) *Leaderboard {
+	if opts.MaxPlayers <= 0 {
+		opts.MaxPlayers = defaultLeaderboardMaxPlayers
+	}
+	return &Leaderboard{maxPlayers: opts.MaxPlayers, excludeBots: opts.ExcludeBots, index: make(map[string]int)}
+}
+
+func (l *Leaderboard) ApplyEvent(e Event) {
+	switch t := e.(type) {
+	case *ServerEvent:
+		if t.Command == CmdInitGame {
+			l.Reset()
+		}
+	case *KillEvent:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.update(t.VictimXUID, t.VictimName, 0, 1)
+		if t.IsSuicide() || t.IsWorldKill() {
+			return
+		}
+		l.update(t.AttackerXUID, t.AttackerName, 1, 0)
+	}
+}
+
+func (l *Leaderboard) Reset() {
+	l.mu.Lock()
+	l.ranks = nil
+	l.index = make(map[string]int)
+	l.mu.Unlock()
+}
+
+// Top returns up to n players, best first.
+func (l *Leaderboard) Top(n int) []PlayerScore {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if n > len(l.ranks) {
+		n = len(l.ranks)
+	}
+	if n <= 0 {
+		return nil
+	}
+	top := make([]PlayerScore, n)
+	for i, p := range l.ranks[:n] {
+		top[i] = *p
+	}
+	return top
+}
+
+func (l *Leaderboard) update(guid, name string, kills, deaths int) {
+	if l.excludeBots && IsBot(guid) {
+		return
+	}
+	key := scoreKey(guid, name)
+	i, ok := l.index[key]
+	if !ok {
+		if len(l.ranks) >= l.maxPlayers {
+			last := l.ranks[len(l.ranks)-1]
+			delete(l.index, scoreKey(last.GUID, last.Name))
+			l.ranks = l.ranks[:len(l.ranks)-1]
+		}
+		i = len(l.ranks)
+		l.ranks = append(l.ranks, &PlayerScore{GUID: guid})
+		l.index[key] = i
+	}
+
+	p := l.ranks[i]
+	p.Name = name
+	p.Kills += kills
+	p.Deaths += deaths
+
+	for i > 0 && scoreLess(l.ranks[i], l.ranks[i-1]) {
+		l.swap(i, i-1)
+		i--
+	}
+	for i < len(l.ranks)-1 && scoreLess(l.ranks[i+1], l.ranks[i]) {
+		l.swap(i, i+1)
+		i++
+	}
+}
+
+func (l *Leaderboard) swap(i, j int) {
+	l.ranks[i], l.ranks[j] = l.ranks[j], l.ranks[i]
+	l.index[scoreKey(l.ranks[i].GUID, l.ranks[i].Name)] = i
+	l.index[scoreKey(l.ranks[j].GUID, l.ranks[j].Name)] = j
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLeaderboardMaxPlayers(t *testing.T) {
+	l := NewLeaderboardWithOptions(LeaderboardOptions{MaxPlayers: 8})
+
+	star := &KillEvent{AttackerXUID: "star", AttackerName: "Star", AttackerClientNum: 0, VictimClientNum: 1}
+	for i := 0; i < 1000; i++ {
+		star.VictimXUID, star.VictimName = fmt.Sprintf("g%d", i), fmt.Sprintf("P%d", i)
+		l.ApplyEvent(star)
+		if n := len(l.Top(1 << 20)); n > 8 {
+			t.Fatalf("%d players tracked, want at most 8", n)
+		}
+	}
+
+	top := l.Top(1)
+	if len(top) != 1 || top[0].GUID != "star" || top[0].Kills != 1000 {
+		t.Errorf("Top(1) = %+v, want star with 1000 kills", top)
+	}
+}
+
+func TestExcludeBotsFromScores(t *testing.T) {
+	kills := []Event{
+		&KillEvent{AttackerXUID: "bot1", AttackerName: "Bot", AttackerClientNum: 1, VictimXUID: "aa", VictimName: "Alice", VictimClientNum: 0},
+		&KillEvent{AttackerXUID: "bot1", AttackerName: "Bot", AttackerClientNum: 1, VictimXUID: "aa", VictimName: "Alice", VictimClientNum: 0},
+		&KillEvent{AttackerXUID: "aa", AttackerName: "Alice", AttackerClientNum: 0, VictimXUID: "bot1", VictimName: "Bot", VictimClientNum: 1},
+	}
+	want := []PlayerScore{{GUID: "aa", Name: "Alice", Kills: 1, Deaths: 2}}
+
+	l := NewLeaderboardWithOptions(LeaderboardOptions{ExcludeBots: true})
+	for _, e := range kills {
+		l.ApplyEvent(e)
+	}
+	if got := l.Top(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("leaderboard = %+v, want %+v", got, want)
+	}
+
+	s := SummarizeMatchWithOptions(kills, MatchOptions{ExcludeBots: true})
+	if !reflect.DeepEqual(s.Scores, want) || s.TopFragger != want[0] || s.Kills != 3 {
+		t.Errorf("summary = %+v, want scores %+v and 3 kills", s, want)
+	}
+
+	// Bots are ranked by default.
+	if s := SummarizeMatch(kills); s.TopFragger.GUID != "bot1" || len(s.Scores) != 2 {
+		t.Errorf("default summary = %+v, want bot1 on top", s)
+	}
+	l = NewLeaderboard()
+	for _, e := range kills {
+		l.ApplyEvent(e)
+	}
+	if top := l.Top(1); len(top) != 1 || top[0].GUID != "bot1" {
+		t.Errorf("default leaderboard top = %+v, want bot1", top)
+	}
+}
+
+func leaderboardKill(attGUID string, attNum int, attName, vicGUID string, vicNum int, vicName string) *KillEvent {
+	return &KillEvent{
+		AttackerXUID: attGUID, AttackerClientNum: attNum, AttackerName: attName,
+		VictimXUID: vicGUID, VictimClientNum: vicNum, VictimName: vicName,
+	}
+}
+
+func TestLeaderboardRanking(t *testing.T) {
+	kills := []Event{
+		leaderboardKill("cc", 2, "Carl", "dd", 3, "Dave"),
+		leaderboardKill("aa", 0, "Alice", "ee", 4, "Eve"),
+		leaderboardKill("bb", 1, "Bob", "ee", 4, "Eve"),
+		leaderboardKill("dd", 3, "Dave", "bb", 1, "Bob"),
+		leaderboardKill("cc", 2, "Carl", "dd", 3, "Dave"),
+		leaderboardKill("ab", 5, "Abe", "ee", 4, "Eve"),
+		leaderboardKill("", -1, "", "ee", 4, "Eve"),
+		leaderboardKill("ee", 4, "Eve", "ee", 4, "Eve"),
+	}
+	// Most kills first, then fewest deaths, then name.
+	want := []PlayerScore{
+		{GUID: "cc", Name: "Carl", Kills: 2},
+		{GUID: "ab", Name: "Abe", Kills: 1},
+		{GUID: "aa", Name: "Alice", Kills: 1},
+		{GUID: "bb", Name: "Bob", Kills: 1, Deaths: 1},
+		{GUID: "dd", Name: "Dave", Kills: 1, Deaths: 2},
+		{GUID: "ee", Name: "Eve", Deaths: 5},
+	}
+
+	l := NewLeaderboard()
+	for _, e := range kills {
+		l.ApplyEvent(e)
+	}
+	if got := l.Top(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Top = %+v\nwant %+v", got, want)
+	}
+	if got := l.Top(2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("Top(2) = %+v, want %+v", got, want[:2])
+	}
+	if got := SummarizeMatch(kills).Scores; !reflect.DeepEqual(got, want) {
+		t.Errorf("leaderboard and SummarizeMatch disagree: %+v", got)
+	}
+}
+
+func TestLeaderboardResetsOnInitGame(t *testing.T) {
+	l := NewLeaderboard()
+	l.ApplyEvent(leaderboardKill("aa", 0, "Alice", "bb", 1, "Bob"))
+	l.ApplyEvent(&ServerEvent{BaseEvent: BaseEvent{Command: CmdInitGame}})
+	if top := l.Top(10); len(top) != 0 {
+		t.Fatalf("Top after InitGame = %+v, want empty", top)
+	}
+
+	l.ApplyEvent(leaderboardKill("bb", 1, "Bob", "aa", 0, "Alice"))
+	want := []PlayerScore{{GUID: "bb", Name: "Bob", Kills: 1}, {GUID: "aa", Name: "Alice", Deaths: 1}}
+	if got := l.Top(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Top = %+v, want %+v", got, want)
+	}
+
+	// Other server events leave the ranking alone.
+	l.ApplyEvent(&ServerEvent{BaseEvent: BaseEvent{Command: CmdShutdownGame}})
+	if got := l.Top(10); len(got) != 2 {
+		t.Errorf("ShutdownGame changed the ranking: %+v", got)
+	}
+}
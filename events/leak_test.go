@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForGoroutines fails the test unless the number of goroutines drops to
+// at most n.
+func waitForGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines still running, want at most %d:\n%s", runtime.NumGoroutine(), n, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBackgroundGoroutinesExitOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games_mp.log")
+	writeFile(t, path, "J;aa;1;Alice\nJ;bb;2;Bob\n")
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Each transform gets an input that stays open and one event it cannot
+	// deliver, so cancellation has to interrupt a blocked send.
+	transforms := map[string]func(<-chan Event) <-chan Event{
+		"Filter":               func(in <-chan Event) <-chan Event { return Filter(ctx, in, func(Event) bool { return true }) },
+		"CoalesceChat":         func(in <-chan Event) <-chan Event { return CoalesceChat(ctx, in, time.Millisecond) },
+		"CorrelateFinalScores": func(in <-chan Event) <-chan Event { return CorrelateFinalScores(ctx, in) },
+		"EnrichChatTeams": func(in <-chan Event) <-chan Event {
+			return EnrichChatTeams(ctx, in, NewPlayerDirectory(staticSource(nil), 0))
+		},
+		"ValidateTimestamps": func(in <-chan Event) <-chan Event { return ValidateTimestamps(ctx, in, func(TimestampAnomaly) {}) },
+		"DetectRenames":      func(in <-chan Event) <-chan Event { return DetectRenames(ctx, in) },
+		"AnnotateRoundTime":  func(in <-chan Event) <-chan Event { return AnnotateRoundTime(ctx, in) },
+		"FillKillWeapons":    func(in <-chan Event) <-chan Event { return FillKillWeapons(ctx, in) },
+	}
+	var outs []<-chan Event
+	for _, transform := range transforms {
+		in := make(chan Event, 1)
+		in <- &PlayerEvent{BaseEvent: BaseEvent{Command: CmdSay}, XUID: "aa", Message: "hi"}
+		outs = append(outs, transform(in))
+	}
+
+	batchIn := make(chan Event, 1)
+	batchIn <- &BaseEvent{Command: "x"}
+	batches := Batch(ctx, batchIn, 10, time.Millisecond)
+
+	jsonl, _ := ReadJSONL(strings.NewReader(strings.Repeat(`{"type":"player","event":{"Command":"say"}}`+"\n", 10)))
+
+	modDone := make(chan error, 1)
+	go func() { modDone <- NewModerationState().Run(ctx, time.Millisecond) }()
+
+	tailDone := make(chan error, 2)
+	go func() { tailDone <- TailFileWithOptions(ctx, path, TailOptions{}, make(chan Event)) }()
+	go func() {
+		tailDone <- TailFileConcurrent(ctx, path, ConcurrentTailOptions{Workers: 4, Ordered: true}, make(chan Event))
+	}()
+
+	started, stop, err := StartTailer(ctx, path, TailOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	for _, out := range outs {
+		for range out {
+		}
+	}
+	for range batches {
+	}
+	for range jsonl {
+	}
+	<-modDone
+	<-tailDone
+	<-tailDone
+	if err := stop(); err != nil {
+		t.Errorf("stop: %v", err)
+	}
+
+	waitForGoroutines(t, before)
+}
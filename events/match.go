@@ -0,0 +1,190 @@
+package events
+
+import (
+	"sort"
+	"time"
+)
+
+type PlayerScore struct {
+	GUID   string
+	Name   string
+	Kills  int
+	Deaths int
+}
+
+type MatchSummary struct {
+	Map      string
+	GameType string
+	Start    *time.Duration
+	End      *time.Duration
+	Duration time.Duration
+
+	// Complete is false when the match has no ShutdownGame, e.g. a log that
+	// was cut off mid-match; the summary then covers events up to the end.
+	Complete bool
+
+	Kills  int
+	Scores []PlayerScore
+
+	TopFragger PlayerScore
+}
+
+type MatchOptions struct {
+	// ExcludeBots leaves players for which IsBot reports true out of Scores
+	// and TopFragger. Kills between a bot and a human still count for the
+	// human and in Kills. Bots are included by default.
+	ExcludeBots bool
+}
+
+// SummarizeMatch summarizes the first match in events. Events before the
+// first InitGame are ignored unless the slice contains no InitGame at all.
+func SummarizeMatch(events []Event) MatchSummary {
+	return SummarizeMatchWithOptions(events, MatchOptions{})
+}
+
+func SummarizeMatchWithOptions(events []Event, opts MatchOptions) MatchSummary {
+	matches := SummarizeMatchesWithOptions(events, opts)
+	if len(matches) == 0 {
+		return MatchSummary{}
+	}
+	return matches[0]
+}
+
+// SummarizeMatches splits events on InitGame and summarizes each match.
+func SummarizeMatches(events []Event) []MatchSummary {
+	return SummarizeMatchesWithOptions(events, MatchOptions{})
+}
+
+func SummarizeMatchesWithOptions(events []Event, opts MatchOptions) []MatchSummary {
+	var summaries []MatchSummary
+	var cur *matchFolder
+
+	hasInit := false
+	for _, e := range events {
+		if isInitGame(e) {
+			hasInit = true
+			break
+		}
+	}
+
+	for _, e := range events {
+		if isInitGame(e) {
+			if cur != nil {
+				summaries = append(summaries, cur.summary())
+			}
+			cur = newMatchFolder(opts)
+		}
+		if cur == nil {
+			if hasInit {
+				continue
+			}
+			cur = newMatchFolder(opts)
+		}
+
+		cur.add(e)
+		if e.GetCommand() == CmdShutdownGame {
+			summaries = append(summaries, cur.summary())
+			cur = nil
+		}
+	}
+	if cur != nil {
+		summaries = append(summaries, cur.summary())
+	}
+	return summaries
+}
+
+type matchFolder struct {
+	opts   MatchOptions
+	s      MatchSummary
+	scores map[string]*PlayerScore
+}
+
+func newMatchFolder(opts MatchOptions) *matchFolder {
+	return &matchFolder{opts: opts, scores: make(map[string]*PlayerScore)}
+}
+
+func (f *matchFolder) add(e Event) {
+	if ts := e.GetTimestamp(); ts != nil {
+		if f.s.Start == nil {
+			start := *ts
+			f.s.Start = &start
+		}
+		end := *ts
+		f.s.End = &end
+	}
+
+	switch t := e.(type) {
+	case *ServerEvent:
+		switch t.Command {
+		case CmdInitGame:
+			f.s.Map = t.Data["mapname"]
+			f.s.GameType = t.Data["g_gametype"]
+		case CmdShutdownGame:
+			f.s.Complete = true
+		}
+	case *KillEvent:
+		f.count(t.VictimXUID, t.VictimName, 0, 1)
+		if t.IsSuicide() || t.IsWorldKill() {
+			return
+		}
+		f.s.Kills++
+		f.count(t.AttackerXUID, t.AttackerName, 1, 0)
+	}
+}
+
+func (f *matchFolder) count(guid, name string, kills, deaths int) {
+	if f.opts.ExcludeBots && IsBot(guid) {
+		return
+	}
+	key := scoreKey(guid, name)
+	p, ok := f.scores[key]
+	if !ok {
+		p = &PlayerScore{GUID: guid}
+		f.scores[key] = p
+	}
+	p.Name = name
+	p.Kills += kills
+	p.Deaths += deaths
+}
+
+func (f *matchFolder) summary() MatchSummary {
+	s := f.s
+	if s.Start != nil && s.End != nil {
+		s.Duration = *s.End - *s.Start
+	}
+
+	s.Scores = make([]PlayerScore, 0, len(f.scores))
+	for _, p := range f.scores {
+		s.Scores = append(s.Scores, *p)
+	}
+	sort.Slice(s.Scores, func(i, j int) bool {
+		return scoreLess(&s.Scores[i], &s.Scores[j])
+	})
+	if len(s.Scores) > 0 && s.Scores[0].Kills > 0 {
+		s.TopFragger = s.Scores[0]
+	}
+	return s
+}
+
+// scoreKey identifies a player by GUID, or by name when the GUID is unknown.
+func scoreKey(guid, name string) string {
+	if guid == "" {
+		return "name:" + name
+	}
+	return guid
+}
+
+// scoreLess orders players by most kills, then fewest deaths, then name.
+func scoreLess(a, b *PlayerScore) bool {
+	if a.Kills != b.Kills {
+		return a.Kills > b.Kills
+	}
+	if a.Deaths != b.Deaths {
+		return a.Deaths < b.Deaths
+	}
+	return a.Name < b.Name
+}
+
+func isInitGame(e Event) bool {
+	return e.GetCommand() == CmdInitGame
+}
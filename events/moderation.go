@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModerationState tracks muted players. Mutes are keyed by GUID whenever the
+// client's GUID is known from a join event, so a player who reconnects into a
+// different slot stays muted. A mute placed on a client number whose GUID is
+// not known yet only lasts until that slot is vacated or reused.
+//
+// A zero until time mutes indefinitely. Expired mutes are dropped lazily on
+// lookup and by Sweep.
+type ModerationState struct {
+	clock        clock
+	mu           sync.Mutex
+	guidByClient map[int]string
+	mutedGUIDs   map[string]time.Time
+	mutedClients map[int]time.Time
+}
+
+func NewModerationState() *ModerationState {
+	return &ModerationState{
+		clock:        realClock{},
+		guidByClient: make(map[int]string),
+		mutedGUIDs:   make(map[string]time.Time),
+		mutedClients: make(map[int]time.Time),
+	}
+}
+
+func (m *ModerationState) ApplyEvent(e Event) {
+	if j, ok := e.(*JoinEvent); ok {
+		e = j.AsPlayerEvent()
+	}
+	p, ok := e.(*PlayerEvent)
+	if !ok || !validClientNum(p.Flag) {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch p.Command {
+	case CmdJoin:
+		delete(m.mutedClients, p.Flag)
+		if guid := moderationGUID(p.XUID); guid != "" {
+			m.guidByClient[p.Flag] = guid
+		} else {
+			delete(m.guidByClient, p.Flag)
+		}
+	case CmdQuit:
+		delete(m.mutedClients, p.Flag)
+		delete(m.guidByClient, p.Flag)
+	}
+}
+
+func (m *ModerationState) Mute(clientNum int, until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if guid, ok := m.guidByClient[clientNum]; ok {
+		m.mutedGUIDs[guid] = until
+		return
+	}
+	m.mutedClients[clientNum] = until
+}
+
+func (m *ModerationState) MuteGUID(guid string, until time.Time) {
+	guid = moderationGUID(guid)
+	if guid == "" {
+		return
+	}
+
+	m.mu.Lock()
+	m.mutedGUIDs[guid] = until
+	m.mu.Unlock()
+}
+
+func (m *ModerationState) Unmute(clientNum int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if guid, ok := m.guidByClient[clientNum]; ok {
+		delete(m.mutedGUIDs, guid)
+	}
+	delete(m.mutedClients, clientNum)
+}
+
+func (m *ModerationState) IsMuted(clientNum int) bool {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if guid, ok := m.guidByClient[clientNum]; ok {
+		return m.guidMutedLocked(guid, now)
+	}
+
+	until, ok := m.mutedClients[clientNum]
+	if !ok {
+		return false
+	}
+	if muteExpired(until, now) {
+		delete(m.mutedClients, clientNum)
+		return false
+	}
+	return true
+}
+
+func (m *ModerationState) IsMutedGUID(guid string) bool {
+	guid = moderationGUID(guid)
+	if guid == "" {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.guidMutedLocked(guid, m.clock.Now())
+}
+
+func (m *ModerationState) Sweep() {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for guid, until := range m.mutedGUIDs {
+		if muteExpired(until, now) {
+			delete(m.mutedGUIDs, guid)
+		}
+	}
+	for clientNum, until := range m.mutedClients {
+		if muteExpired(until, now) {
+			delete(m.mutedClients, clientNum)
+		}
+	}
+}
+
+// Run calls Sweep every interval until ctx is cancelled.
+func (m *ModerationState) Run(ctx context.Context, interval time.Duration) error {
+	ticker := m.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.Chan():
+			m.Sweep()
+		}
+	}
+}
+
+func (m *ModerationState) guidMutedLocked(guid string, now time.Time) bool {
+	until, ok := m.mutedGUIDs[guid]
+	if !ok {
+		return false
+	}
+	if muteExpired(until, now) {
+		delete(m.mutedGUIDs, guid)
+		return false
+	}
+	return true
+}
+
+func muteExpired(until, now time.Time) bool {
+	return !until.IsZero() && !now.Before(until)
+}
+
+// moderationGUID normalizes guid, returning "" for the "0" that bots and
+// unauthenticated clients join with, so their mutes stay keyed by client
+// number instead of all sharing one GUID.
+func moderationGUID(guid string) string {
+	guid = strings.ToLower(strings.TrimSpace(guid))
+	if guid == "0" {
+		return ""
+	}
+	return guid
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModerationMuteExpires(t *testing.T) {
+	clk := newFakeClock()
+	m := NewModerationState()
+	m.clock = clk
+
+	m.Mute(3, clk.Now().Add(time.Minute))
+	m.Mute(4, time.Time{})
+	if !m.IsMuted(3) || !m.IsMuted(4) {
+		t.Fatal("clients not muted")
+	}
+
+	clk.Advance(time.Minute)
+	if m.IsMuted(3) {
+		t.Error("timed mute still active after it expired")
+	}
+	if !m.IsMuted(4) {
+		t.Error("indefinite mute expired")
+	}
+}
+
+func TestModerationMuteFollowsGUID(t *testing.T) {
+	m := NewModerationState()
+	m.ApplyEvent(&JoinEvent{BaseEvent: BaseEvent{Command: CmdJoin}, GUID: "AA", ClientNum: 3, Name: "Alice"})
+	m.Mute(3, time.Time{})
+
+	m.ApplyEvent(&PlayerEvent{BaseEvent: BaseEvent{Command: CmdQuit}, XUID: "aa", Flag: 3, Player: "Alice"})
+	if m.IsMuted(3) {
+		t.Error("vacated slot still muted")
+	}
+	m.ApplyEvent(&JoinEvent{BaseEvent: BaseEvent{Command: CmdJoin}, GUID: "aa", ClientNum: 7, Name: "Alice"})
+	if !m.IsMuted(7) {
+		t.Error("mute lost on reconnect into a new slot")
+	}
+	if !m.IsMutedGUID(" Aa ") {
+		t.Error("IsMutedGUID does not normalize the GUID")
+	}
+}
+
+func TestModerationZeroGUIDsMutedSeparately(t *testing.T) {
+	m := NewModerationState()
+	m.ApplyEvent(&JoinEvent{BaseEvent: BaseEvent{Command: CmdJoin}, GUID: "0", ClientNum: 1, Name: "Bot1"})
+	m.ApplyEvent(&JoinEvent{BaseEvent: BaseEvent{Command: CmdJoin}, GUID: "0", ClientNum: 2, Name: "Guest"})
+
+	m.Mute(1, time.Time{})
+	if !m.IsMuted(1) {
+		t.Fatal("client 1 not muted")
+	}
+	if m.IsMuted(2) {
+		t.Error("muting one GUID-less client muted another")
+	}
+	if m.IsMutedGUID("0") {
+		t.Error(`"0" treated as a GUID`)
+	}
+	m.MuteGUID("0", time.Time{})
+	if m.IsMuted(2) {
+		t.Error(`MuteGUID("0") muted a GUID-less client`)
+	}
+}
+
+func TestModerationSweep(t *testing.T) {
+	clk := newFakeClock()
+	m := NewModerationState()
+	m.clock = clk
+
+	m.ApplyEvent(&JoinEvent{BaseEvent: BaseEvent{Command: CmdJoin}, GUID: "aa", ClientNum: 1, Name: "Alice"})
+	m.Mute(1, clk.Now().Add(time.Minute))
+	m.Mute(2, clk.Now().Add(time.Minute))
+	m.MuteGUID("bb", time.Time{})
+
+	clk.Advance(time.Minute)
+	m.Sweep()
+	if len(m.mutedGUIDs) != 1 || len(m.mutedClients) != 0 {
+		t.Errorf("after Sweep: %d GUID and %d client mutes, want 1 and 0", len(m.mutedGUIDs), len(m.mutedClients))
+	}
+	if !m.IsMutedGUID("bb") {
+		t.Error("Sweep dropped an indefinite mute")
+	}
+}
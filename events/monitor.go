@@ -0,0 +1,67 @@
+package events
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TailMonitor exposes the liveness of a running tailer, for watchdogs. Pass
+// one in TailOptions.Monitor, to a single tailer, and call Health from any
+// goroutine; reading it is cheap and takes no locks.
+type TailMonitor struct {
+	alive     atomic.Bool
+	lastRead  atomic.Int64
+	reopens   atomic.Int64
+	errors    atomic.Int64
+	eventsCap atomic.Int64
+	eventsCh  atomic.Value
+}
+
+// TailHealth is a snapshot of a tailer's liveness.
+type TailHealth struct {
+	// Alive is true while the tail function is running.
+	Alive bool
+
+	// LastRead is when the tailer last read a line, and zero if it has not
+	// read any.
+	LastRead time.Time
+
+	// Reopens counts the times the file was reopened after a rotation.
+	Reopens int64
+
+	// ConsecutiveErrors counts the failed reopen attempts and unparsable
+	// lines since the last line that parsed.
+	ConsecutiveErrors int64
+
+	// Backlog is the number of events waiting in the events channel, and
+	// BacklogCap its capacity.
+	Backlog    int
+	BacklogCap int
+}
+
+// Health returns the tailer's current state.
+func (m *TailMonitor) Health() TailHealth {
+	h := TailHealth{
+		Alive:             m.alive.Load(),
+		Reopens:           m.reopens.Load(),
+		ConsecutiveErrors: m.errors.Load(),
+		BacklogCap:        int(m.eventsCap.Load()),
+	}
+	if ns := m.lastRead.Load(); ns != 0 {
+		h.LastRead = time.Unix(0, ns)
+	}
+	if ch, ok := m.eventsCh.Load().(chan<- Event); ok {
+		h.Backlog = len(ch)
+	}
+	return h
+}
+
+func (m *TailMonitor) start(eventsCh chan<- Event) {
+	m.eventsCh.Store(eventsCh)
+	m.eventsCap.Store(int64(cap(eventsCh)))
+	m.alive.Store(true)
+}
+
+func (m *TailMonitor) read(now time.Time) { m.lastRead.Store(now.UnixNano()) }
+func (m *TailMonitor) failed()            { m.errors.Add(1) }
+func (m *TailMonitor) parsed()            { m.errors.Store(0) }
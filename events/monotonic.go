@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// TimestampAnomaly describes an event whose timestamp is earlier than that of
+// a preceding event in the same match.
+type TimestampAnomaly struct {
+	Event    Event
+	Previous time.Duration
+
+	// Epoch is the number of InitGame events seen before Event.
+	Epoch uint64
+}
+
+// Backward returns how far the timestamp went back.
+func (a TimestampAnomaly) Backward() time.Duration {
+	return a.Previous - *a.Event.GetTimestamp()
+}
+
+// ValidateTimestamps forwards the events from in unchanged and calls
+// onBackward, from its own goroutine, for every event whose timestamp is
+// earlier than the latest one seen since the last InitGame. The game clock
+// restarting with a new match is not an anomaly: an InitGame starts a new
+// epoch and resets the expectation. Events without a timestamp are ignored.
+func ValidateTimestamps(ctx context.Context, in <-chan Event, onBackward func(TimestampAnomaly)) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		var epoch uint64
+		var last time.Duration
+		seen := false
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if isInitGame(e) {
+					epoch++
+					seen = false
+				}
+				if ts := e.GetTimestamp(); ts != nil {
+					if seen && *ts < last {
+						if onBackward != nil {
+							onBackward(TimestampAnomaly{Event: e, Previous: last, Epoch: epoch})
+						}
+					} else {
+						last, seen = *ts, true
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ObjectiveAction string
+
+const (
+	ObjectivePlant   ObjectiveAction = "plant"
+	ObjectiveDefuse  ObjectiveAction = "defuse"
+	ObjectiveExplode ObjectiveAction = "explode"
+	ObjectiveCapture ObjectiveAction = "capture"
+	ObjectiveReturn  ObjectiveAction = "return"
+)
+
+type ObjectiveEvent struct {
+	BaseEvent
+	Action    ObjectiveAction
+	XUID      string
+	ClientNum int
+	Player    string
+}
+
+var (
+	objectiveMu sync.RWMutex
+
+	// objectiveCommands holds the stock bomb plant and defuse commands. The
+	// engines log explosions, flag captures and returns under mod-specific
+	// commands, if at all, so ObjectiveExplode, ObjectiveCapture and
+	// ObjectiveReturn are only produced once a command is registered for
+	// them with RegisterObjectiveCommand.
+	objectiveCommands = map[string]ObjectiveAction{
+		"BP": ObjectivePlant,
+		"BD": ObjectiveDefuse,
+	}
+)
+
+// RegisterObjectiveCommand makes lines of the form "<cmd>;guid;num;name" (or
+// a bare "<cmd>" for events with no acting player) parse as ObjectiveEvents
+// with the given action. Only "BP" (plant) and "BD" (defuse) are recognized
+// by default; explode, capture and return commands must be registered.
+func RegisterObjectiveCommand(cmd string, action ObjectiveAction) {
+	objectiveMu.Lock()
+	objectiveCommands[cmd] = action
+	objectiveMu.Unlock()
+}
+
+func lookupObjectiveCommand(cmd string) (ObjectiveAction, bool) {
+	objectiveMu.RLock()
+	action, ok := objectiveCommands[cmd]
+	objectiveMu.RUnlock()
+	return action, ok
+}
+
+func parseObjectiveEvent(line string, ts *time.Duration, raw string, base int) (*ObjectiveEvent, error) {
+	parts := strings.SplitN(line, ";", 4)
+	action, ok := lookupObjectiveCommand(parts[0])
+	if !ok {
+		return nil, fmt.Errorf("not an objective event")
+	}
+
+	ev := &ObjectiveEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   parts[0],
+			Raw:       raw,
+		},
+		Action:    action,
+		ClientNum: -1,
+	}
+
+	if len(parts) == 1 {
+		return ev, nil
+	}
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid objective event line: %q", line)
+	}
+
+	clientNum, err := parseClientNum(parts[2], base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", parts[2], err)
+	}
+
+	ev.XUID = parts[1]
+	ev.ClientNum = clientNum
+	ev.Player = parts[3]
+	return ev, nil
+}
@@ -0,0 +1,49 @@
+package events
+
+import "testing"
+
+func TestParseObjectiveDefaultCommands(t *testing.T) {
+	tests := []struct {
+		line   string
+		action ObjectiveAction
+		num    int
+		player string
+	}{
+		{"BP;aa;3;Alice", ObjectivePlant, 3, "Alice"},
+		{"12:34 BD;bb;5;Bob", ObjectiveDefuse, 5, "Bob"},
+		{"BP", ObjectivePlant, -1, ""},
+		{"BD", ObjectiveDefuse, -1, ""},
+	}
+	for _, tt := range tests {
+		o, ok := mustParse(t, tt.line, ParseOptions{}).(*ObjectiveEvent)
+		if !ok {
+			t.Errorf("%q not parsed as an objective", tt.line)
+			continue
+		}
+		if o.Action != tt.action || o.ClientNum != tt.num || o.Player != tt.player {
+			t.Errorf("%q parsed as %+v", tt.line, o)
+		}
+	}
+
+	for _, line := range []string{"BE;aa;3;Alice", "FC;aa;3;Alice", "BP;aa;3"} {
+		if e, err := ParseEventLine(line); err == nil {
+			if _, ok := e.(*ObjectiveEvent); ok {
+				t.Errorf("%q parsed as an objective without registering it", line)
+			}
+		}
+	}
+}
+
+func TestRegisterObjectiveCommand(t *testing.T) {
+	RegisterObjectiveCommand("TestFC", ObjectiveCapture)
+	t.Cleanup(func() {
+		objectiveMu.Lock()
+		delete(objectiveCommands, "TestFC")
+		objectiveMu.Unlock()
+	})
+
+	o, ok := mustParse(t, "TestFC;aa;3;Alice", ParseOptions{}).(*ObjectiveEvent)
+	if !ok || o.Action != ObjectiveCapture || o.XUID != "aa" {
+		t.Errorf("registered command parsed as %#v", o)
+	}
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -8,133 +9,538 @@ import (
 	"time"
 )
 
-func parseJoinEvent(line string, ts *time.Duration, raw string) (*PlayerEvent, error) {
-	m := regexp.MustCompile(`^(J);(-?[A-Fa-f0-9_]{1,32}|bot[0-9]+|0);([0-9]+);(.*)$`).FindStringSubmatch(line)
+var ErrMalformed = errors.New("malformed event line")
+
+// ErrCommentLine is returned for lines starting with one of
+// ParseOptions.CommentPrefixes. The tailers and EventScanner skip such lines
+// silently.
+var ErrCommentLine = errors.New("comment line")
+
+const maxStrictClientNum = 255
+
+// validClientNum reports whether n is in the range of client numbers the
+// engine writes. Stateful types keyed by client number ignore others, which
+// bounds their size.
+func validClientNum(n int) bool {
+	return n >= 0 && n <= maxStrictClientNum
+}
+
+var joinPattern = regexp.MustCompile(`^(J);(-?[A-Fa-f0-9_]{1,32}|bot[0-9]+|0);([0-9A-Za-z]+);(.*)$`)
+
+var strictGUIDPattern = regexp.MustCompile(`^(-?[A-Fa-f0-9_]{1,32}|bot[0-9]+|0)$`)
+
+type ParseOptions struct {
+	// Strict rejects player lines whose GUID or client number does not look
+	// like one the engine would write, and kill and damage lines with fields
+	// missing, returning an error wrapping ErrMalformed. The default is
+	// lenient, which keeps such kill and damage lines as unrecognized lines.
+	Strict bool
+
+	// NoTimestamps skips leading timestamp detection for logs known not to
+	// carry one.
+	NoTimestamps bool
+
+	// UnknownEvents makes unrecognized lines parse as *UnknownEvent rather
+	// than a *BaseEvent whose Command is the whole line.
+	UnknownEvents bool
+
+	// NormalizeCommands matches the leading command of a line against the
+	// known commands case-insensitively and rewrites it to the canonical
+	// casing of the Cmd* constants (or the registered objective command), so
+	// that e.g. "initgame:" and "k;..." parse like "InitGame:" and "K;...".
+	// Raw keeps the original line.
+	NormalizeCommands bool
+
+	// CommentPrefixes lists prefixes, such as "//" or "#", that mark a line,
+	// with or without a timestamp, as a comment. None are recognized by
+	// default.
+	CommentPrefixes []string
+
+	// StripLogLevel removes a leading log-level token added by a logging
+	// wrapper, as in "INFO: <line>" or "[WARN] <line>", before timestamp and
+	// event detection. The token must be followed by a colon or enclosed in
+	// brackets, and is matched case-insensitively against LogLevels, or
+	// against TRACE, DEBUG, INFO, WARN, WARNING, ERROR and FATAL if
+	// LogLevels is empty. Raw keeps the original line.
+	StripLogLevel bool
+	LogLevels     []string
+
+	// WeaponMapper, if set, rewrites the weapon of kill and damage events as
+	// they are parsed, e.g. to canonicalize a mod's weapon names, with the
+	// logged value kept in KillEvent.RawWeapon. Weapon methods such as Base
+	// then apply to the mapped value. By default the weapon is left as
+	// logged.
+	WeaponMapper func(raw string) string
+
+	// TimestampFormat restricts timestamp detection to one format. With the
+	// default, TimestampAuto, ParseEventLine accepts either format on every
+	// line, while the tailer and EventScanner detect the format from the
+	// first lines of a file and lock it in (see TimestampFormat).
+	TimestampFormat TimestampFormat
+
+	// ClientNumBase is the base of the client numbers in join, quit, chat,
+	// tell, kill, damage, objective, voice, spawn and item lines, for engines
+	// that do not log them in decimal.
+	// Zero means 10. With base 16 a "0x" prefix is accepted, so "0x0a" is
+	// client 10.
+	ClientNumBase int
+}
+
+// TimestampFormat is the format of the leading timestamp of log lines.
+//
+// The tailer and EventScanner lock in the format of the first line that
+// carries a timestamp, or TimestampNone once the first 20 lines carry none,
+// so that later lines are only split in that format. The tailer detects
+// again after a rotation.
+type TimestampFormat int
+
+const (
+	TimestampAuto TimestampFormat = iota
+	// TimestampNone treats every line as untimed, like NoTimestamps.
+	TimestampNone
+	// TimestampMinutes is "M:SS", e.g. "12:34".
+	TimestampMinutes
+	// TimestampHours is "H:MM:SS", e.g. "1:02:03".
+	TimestampHours
+)
+
+// timestampDetectLines is the number of untimed lines after which the
+// detected format is locked to TimestampNone.
+const timestampDetectLines = 20
+
+// timestampDetector locks in the timestamp format of a file.
+type timestampDetector struct {
+	format  TimestampFormat
+	untimed int
+}
+
+// apply returns opts with the detected format, after observing line.
+func (d *timestampDetector) apply(line string, opts ParseOptions) ParseOptions {
+	if opts.NoTimestamps || opts.TimestampFormat != TimestampAuto {
+		return opts
+	}
+	if d.format == TimestampAuto {
+		line = strings.TrimSpace(line)
+		if opts.StripLogLevel {
+			line = stripLogLevel(line, opts.LogLevels)
+		}
+		if format := leadingTimestampFormat(line); format != TimestampNone {
+			d.format = format
+		} else if d.untimed++; d.untimed >= timestampDetectLines {
+			d.format = TimestampNone
+		}
+	}
+	opts.TimestampFormat = d.format
+	return opts
+}
+
+func leadingTimestampFormat(line string) TimestampFormat {
+	ts, _ := splitTimestamp(line, TimestampAuto)
+	if ts == nil {
+		return TimestampNone
+	}
+	if strings.Count(line[:strings.IndexAny(line, " \t")], ":") == 2 {
+		return TimestampHours
+	}
+	return TimestampMinutes
+}
+
+var defaultLogLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "WARNING", "ERROR", "FATAL"}
+
+// stripLogLevel removes a leading "LEVEL:", "[LEVEL]" or "[LEVEL]:" token
+// from line if LEVEL is one of levels.
+func stripLogLevel(line string, levels []string) string {
+	if len(levels) == 0 {
+		levels = defaultLogLevels
+	}
+
+	var token, rest string
+	if strings.HasPrefix(line, "[") {
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return line
+		}
+		token, rest = line[1:end], line[end+1:]
+		rest = strings.TrimPrefix(rest, ":")
+	} else {
+		end := strings.IndexByte(line, ':')
+		if end < 0 {
+			return line
+		}
+		token, rest = line[:end], line[end+1:]
+	}
+
+	token = strings.TrimSpace(token)
+	for _, level := range levels {
+		if strings.EqualFold(token, level) {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return line
+}
+
+func parseJoinEvent(line string, ts *time.Duration, raw string, opts ParseOptions) (*JoinEvent, error) {
+	m := joinPattern.FindStringSubmatch(line)
 	if m == nil {
 		return nil, fmt.Errorf("not a join event")
 	}
 
 	cmd := m[1]
-	originNet := m[2]
+	guid := m[2]
 	clientNumStr := m[3]
-	originName := m[4]
+	name, team := splitJoinTeam(m[4])
 
-	clientNum, err := strconv.Atoi(clientNumStr)
+	clientNum, err := parseClientNum(clientNumStr, opts.ClientNumBase)
 	if err != nil {
 		return nil, fmt.Errorf("invalid client number %q: %w", clientNumStr, err)
 	}
+	if opts.Strict && !validClientNum(clientNum) {
+		return nil, fmt.Errorf("%w: client number %d out of range", ErrMalformed, clientNum)
+	}
 
-	return &PlayerEvent{
+	return &JoinEvent{
 		BaseEvent: BaseEvent{
 			Timestamp: ts,
 			Command:   cmd,
 			Raw:       raw,
 		},
-		XUID:    originNet,
-		Flag:    clientNum,
-		Player:  originName,
-		Message: "",
+		GUID:      guid,
+		ClientNum: clientNum,
+		Name:      name,
+		Team:      team,
 	}, nil
 }
 
-func parseKillEvent(line string, ts *time.Duration, raw string) (*KillEvent, error) {
+// splitJoinTeam splits a trailing team field off the rest of a join line,
+// after the client number. Everything else is the name, which may contain
+// ';', so the last field is only taken as the team if it is "allies", "axis"
+// or "spectator" (or an alias ParseTeam maps to those).
+func splitJoinTeam(rest string) (name, team string) {
+	i := strings.LastIndexByte(rest, ';')
+	if i < 0 {
+		return rest, ""
+	}
+	switch ParseTeam(rest[i+1:]) {
+	case TeamAllies, TeamAxis, TeamSpectator:
+		return rest[:i], strings.TrimSpace(rest[i+1:])
+	}
+	return rest, ""
+}
+
+func parseKillEvent(line string, ts *time.Duration, raw string, opts ParseOptions) (*KillEvent, error) {
+	return parseKillLine(CmdKill, line, ts, raw, opts)
+}
+
+// parseKillLine parses a kill-shaped line, i.e. a K or D line, whose first
+// field must be cmd.
+func parseKillLine(cmd, line string, ts *time.Duration, raw string, opts ParseOptions) (*KillEvent, error) {
 	parts := strings.Split(line, ";")
-	if len(parts) != 13 {
-		return nil, fmt.Errorf("not a kill event - expected 13 fields, got %d", len(parts))
+	parts, extra := splitKillExtra(parts)
+	if len(parts) < 13 {
+		return nil, fmt.Errorf("not a kill event - expected at least 13 fields, got %d", len(parts))
 	}
 
-	if parts[0] != "K" {
-		return nil, fmt.Errorf("not a kill event")
+	if parts[0] != cmd {
+		return nil, fmt.Errorf("not a %s event", cmd)
 	}
 
-	AttackerClientNum, err := strconv.Atoi(parts[2])
+	// Names may contain ';', so the fields are located from the means of
+	// death backwards and the victim's guid;num;team triple is searched for
+	// between the two names.
+	mod := killMODIndex(parts)
+	weapon := mod - 2
+	victim := killVictimIndex(parts, weapon, opts.ClientNumBase)
+
+	AttackerClientNum, err := parseClientNum(parts[2], opts.ClientNumBase)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Attacker client number %q: %w", parts[2], err)
 	}
 
-	victimClientNum, err := strconv.Atoi(parts[6])
+	victimClientNum, err := parseClientNum(parts[victim+1], opts.ClientNumBase)
 	if err != nil {
-		return nil, fmt.Errorf("invalid victim client number %q: %w", parts[6], err)
+		return nil, fmt.Errorf("invalid victim client number %q: %w", parts[victim+1], err)
+	}
+
+	// After the hit location come either a distance, the armor and shield
+	// damage, or all three.
+	var distance *float64
+	var armor, shield *int
+	trailing := parts[mod+2:]
+	if len(trailing) == 1 || len(trailing) == 3 {
+		if d, err := strconv.ParseFloat(strings.TrimSpace(trailing[0]), 64); err == nil {
+			distance = &d
+		}
+		trailing = trailing[1:]
+	}
+	if len(trailing) == 2 {
+		armor = parseOptionalInt(trailing[0])
+		shield = parseOptionalInt(trailing[1])
 	}
 
 	return &KillEvent{
 		BaseEvent: BaseEvent{
 			Timestamp: ts,
-			Command:   "K",
+			Command:   cmd,
 			Raw:       raw,
 		},
 		AttackerXUID:      parts[1],
 		AttackerClientNum: AttackerClientNum,
 		AttackerTeam:      parts[3],
-		AttackerName:      parts[4],
-		VictimXUID:        parts[5],
+		AttackerName:      strings.Join(parts[4:victim], ";"),
+		VictimXUID:        parts[victim],
 		VictimClientNum:   victimClientNum,
-		VictimTeam:        parts[7],
-		VictimName:        parts[8],
-		Weapon:            parts[9],
-		Damage:            parts[10],
-		MeansOfDeath:      parts[11],
-		HitLocation:       parts[12],
+		VictimTeam:        parts[victim+2],
+		VictimName:        strings.Join(parts[victim+3:weapon], ";"),
+		Weapon:            Weapon(parts[weapon]),
+		Damage:            parts[weapon+1],
+		MeansOfDeath:      parts[mod],
+		HitLocation:       parts[mod+1],
+		Distance:          distance,
+		ArmorDamage:       armor,
+		ShieldDamage:      shield,
+		Extra:             extra,
 	}, nil
 }
 
+func mapWeapon(k *KillEvent, opts ParseOptions) {
+	if opts.WeaponMapper == nil {
+		return
+	}
+	k.RawWeapon = k.Weapon
+	k.Weapon = Weapon(opts.WeaponMapper(string(k.Weapon)))
+}
+
+// parseClientNum parses a client number in base, or in base 10 if base is
+// zero.
+func parseClientNum(s string, base int) (int, error) {
+	if base == 0 || base == 10 {
+		return strconv.Atoi(s)
+	}
+	if base == 16 && len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		s = s[2:]
+	}
+	n, err := strconv.ParseInt(s, base, 0)
+	return int(n), err
+}
+
+func parseOptionalInt(s string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// splitKillExtra removes the trailing key=value fields from a kill line's
+// fields and returns them as a map.
+func splitKillExtra(parts []string) ([]string, map[string]string) {
+	n := len(parts)
+	for n > 13 {
+		key, _, ok := strings.Cut(parts[n-1], "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			break
+		}
+		n--
+	}
+	if n == len(parts) {
+		return parts, nil
+	}
+
+	extra := make(map[string]string, len(parts)-n)
+	for _, field := range parts[n:] {
+		key, value, _ := strings.Cut(field, "=")
+		extra[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return parts[:n], extra
+}
+
+// killMODIndex returns the index of the means-of-death field, falling back to
+// its position in a line without ';' in names.
+func killMODIndex(parts []string) int {
+	for i := len(parts) - 2; i > 11; i-- {
+		if strings.HasPrefix(parts[i], "MOD_") {
+			return i
+		}
+	}
+	return 11
+}
+
+// killVictimIndex returns the index of the victim's guid, given that the
+// weapon is at index weapon. With no ';' in names it is 5; otherwise the
+// candidate whose client number parses and whose guid and team look valid
+// wins, the earliest on a tie.
+func killVictimIndex(parts []string, weapon, base int) int {
+	best, bestScore := 5, -1
+	for v := 5; v <= weapon-4; v++ {
+		if _, err := parseClientNum(parts[v+1], base); err != nil {
+			continue
+		}
+		score := 0
+		if strictGUIDPattern.MatchString(parts[v]) {
+			score++
+		}
+		if team := parts[v+2]; team == "" || ParseTeam(team) != TeamUnknown {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = v, score
+		}
+	}
+	return best
+}
+
 func ParseEventLine(line string) (Event, error) {
+	return ParseEventLineWithOptions(line, ParseOptions{})
+}
+
+func ParseEventLineWithOptions(line string, opts ParseOptions) (Event, error) {
 	line = strings.TrimSpace(line)
 	if line == "" {
 		return nil, fmt.Errorf("empty line")
 	}
 
 	raw := line
+	if opts.StripLogLevel {
+		line = stripLogLevel(line, opts.LogLevels)
+	}
+
 	var ts *time.Duration
+	if !opts.NoTimestamps && opts.TimestampFormat != TimestampNone {
+		ts, line = splitTimestamp(line, opts.TimestampFormat)
+	}
 
-	fields := strings.Fields(line)
-	if len(fields) > 1 {
-		first := fields[0]
-		if strings.Contains(first, ":") {
-			if dur, err := parseTimestamp(first); err == nil {
-				ts = &dur
-				line = strings.Join(fields[1:], " ")
-			}
+	for _, prefix := range opts.CommentPrefixes {
+		if prefix != "" && (strings.HasPrefix(line, prefix) || strings.HasPrefix(raw, prefix)) {
+			return nil, ErrCommentLine
 		}
 	}
 
+	if opts.NormalizeCommands {
+		line = normalizeCommand(line)
+	}
+
+	if !strings.Contains(line, ";") && strings.Contains(line, "\t") {
+		line = sniffTabFields(line)
+	}
+
 	if strings.HasPrefix(line, "InitGame:") {
 		data := parseKeyValuePairs(strings.TrimPrefix(line, "InitGame:"))
 		return &ServerEvent{
 			BaseEvent: BaseEvent{
 				Timestamp: ts,
-				Command:   "InitGame",
+				Command:   CmdInitGame,
 				Raw:       raw,
 			},
 			Data: data,
 		}, nil
 	}
 
-	if strings.HasPrefix(line, "ShutdownGame:") {
+	if isShutdownGameLine(line) {
 		return &ServerEvent{
 			BaseEvent: BaseEvent{
 				Timestamp: ts,
-				Command:   "ShutdownGame",
+				Command:   CmdShutdownGame,
 				Raw:       raw,
 			},
 			Data: map[string]string{},
 		}, nil
 	}
 
+	if reason, ok := strings.CutPrefix(line, CmdExit+":"); ok {
+		reason = strings.TrimSpace(reason)
+		return &ServerEvent{
+			BaseEvent: BaseEvent{
+				Timestamp: ts,
+				Command:   CmdExit,
+				Raw:       raw,
+			},
+			Data:       map[string]string{"reason": reason},
+			ExitReason: ParseExitReason(reason),
+		}, nil
+	}
+
+	if strings.HasPrefix(line, CmdScore+":") {
+		return parseScoreEvent(line, ts, raw)
+	}
+
+	if strings.HasPrefix(line, CmdAward+":") {
+		return parseAwardEvent(line, ts, raw)
+	}
+
+	if ev, err := parseServerMessageEvent(line, ts, raw); err == nil {
+		return ev, nil
+	}
+
+	if strings.HasPrefix(line, CmdCallvote+":") || strings.HasPrefix(line, CmdVote+":") {
+		return parseVoteEvent(line, ts, raw)
+	}
+
+	if ev, err := parseObjectiveEvent(line, ts, raw, opts.ClientNumBase); err == nil {
+		return ev, nil
+	}
+
+	if strings.HasPrefix(line, "tell;") {
+		return parseTellEvent(line, ts, raw, opts.ClientNumBase)
+	}
+
+	if ev, err := parseVoiceEvent(line, ts, raw, opts.ClientNumBase); err == nil {
+		return ev, nil
+	}
+
+	if ev, err := parseSpawnEvent(line, ts, raw, opts.ClientNumBase); err == nil {
+		return ev, nil
+	}
+
 	if strings.Contains(line, ";") {
-		if ev, err := parseJoinEvent(line, ts, raw); err == nil {
+		if ev, err := parseItemEvent(line, ts, raw, opts.ClientNumBase); err == nil {
 			return ev, nil
 		}
-		if ev, err := parseKillEvent(line, ts, raw); err == nil {
+		if ev, err := parseJoinEvent(line, ts, raw, opts); err == nil {
 			return ev, nil
 		}
-		return parsePlayerEvent(line, ts, raw)
+		kill, killErr := parseKillEvent(line, ts, raw, opts)
+		if killErr == nil {
+			mapWeapon(kill, opts)
+			return kill, nil
+		}
+		damage, damageErr := parseDamageEvent(line, ts, raw, opts)
+		if damageErr == nil {
+			mapWeapon(&damage.KillEvent, opts)
+			return damage, nil
+		}
+		p, err := parsePlayerEvent(line, ts, raw, opts)
+		switch {
+		case err == nil && p.Command == CmdJoin:
+			// A join the pattern rejected, e.g. with an unusual GUID.
+			name, team := splitJoinTeam(p.Player)
+			return &JoinEvent{BaseEvent: p.BaseEvent, GUID: p.XUID, ClientNum: p.Flag, Name: name, Team: team}, nil
+		case err == nil && (p.Command == CmdKill || p.Command == CmdDamage):
+			// A kill or damage line with fields missing is not a player line.
+			// Strict mode rejects it; otherwise it is kept as an unrecognized
+			// line below.
+			if opts.Strict {
+				if p.Command == CmdDamage {
+					killErr = damageErr
+				}
+				return nil, fmt.Errorf("%w: %v", ErrMalformed, killErr)
+			}
+		default:
+			return p, err
+		}
 	}
 
 	if strings.HasPrefix(line, "say ") || strings.HasPrefix(line, "sayteam ") {
 		return parseChatPlayerEvent(line, ts, raw)
 	}
 
+	if strings.HasPrefix(line, "tell ") {
+		return parseTellEvent(line, ts, raw, opts.ClientNumBase)
+	}
+
+	if opts.UnknownEvents {
+		return newUnknownEvent(line, ts, raw), nil
+	}
+
 	return &BaseEvent{
 		Timestamp: ts,
 		Command:   line,
@@ -142,7 +548,60 @@ func ParseEventLine(line string) (Event, error) {
 	}, nil
 }
 
-func parsePlayerEvent(line string, ts *time.Duration, raw string) (*PlayerEvent, error) {
+// isShutdownGameLine reports whether line is "ShutdownGame:", the bare word
+// or a banner such as "------ ShutdownGame ------".
+func isShutdownGameLine(line string) bool {
+	if strings.HasPrefix(line, CmdShutdownGame+":") {
+		return true
+	}
+	return strings.Trim(line, "- \t") == CmdShutdownGame
+}
+
+func newUnknownEvent(line string, ts *time.Duration, raw string) *UnknownEvent {
+	cmd := line
+	if i := strings.IndexAny(line, " \t;"); i >= 0 {
+		cmd = line[:i]
+	}
+	return &UnknownEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   strings.TrimSuffix(cmd, ":"),
+			Raw:       raw,
+		},
+		Line: line,
+	}
+}
+
+// NewFromLine parses line like ParseEventLine but never fails: a line that
+// ParseEventLine rejects, such as a player line with a non-numeric client
+// number, becomes an *UnknownEvent, as does a blank line. Use it when every
+// line should yield an event; use ParseEventLine to learn why a line did not
+// parse.
+func NewFromLine(line string) Event {
+	if ev, err := ParseEventLine(line); err == nil && ev != nil {
+		return ev
+	}
+	line = strings.TrimSpace(line)
+	ts, rest := splitTimestamp(line, TimestampAuto)
+	return newUnknownEvent(rest, ts, line)
+}
+
+// ParseLines parses each line with ParseEventLine. The returned slices have
+// the same length as lines and are aligned by index; blank lines leave both
+// entries nil.
+func ParseLines(lines []string) ([]Event, []error) {
+	events := make([]Event, len(lines))
+	errs := make([]error, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		events[i], errs[i] = ParseEventLine(line)
+	}
+	return events, errs
+}
+
+func parsePlayerEvent(line string, ts *time.Duration, raw string, opts ParseOptions) (*PlayerEvent, error) {
 	parts := strings.SplitN(line, ";", 5)
 	if len(parts) < 4 {
 		return nil, fmt.Errorf("invalid player event line: %q", line)
@@ -151,15 +610,32 @@ func parsePlayerEvent(line string, ts *time.Duration, raw string) (*PlayerEvent,
 	cmd := strings.TrimSpace(parts[0])
 	xuid := strings.TrimSpace(parts[1])
 
-	flag, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+	flag, err := parseClientNum(strings.TrimSpace(parts[2]), opts.ClientNumBase)
 	if err != nil {
 		return nil, fmt.Errorf("invalid flag %q: %w", parts[2], err)
 	}
 
+	if opts.Strict {
+		if cmd == "" || strings.ContainsAny(cmd, " \t") {
+			return nil, fmt.Errorf("%w: invalid command %q", ErrMalformed, cmd)
+		}
+		if !strictGUIDPattern.MatchString(xuid) {
+			return nil, fmt.Errorf("%w: invalid guid %q", ErrMalformed, xuid)
+		}
+		if flag < 0 || flag > maxStrictClientNum {
+			return nil, fmt.Errorf("%w: client number %d out of range", ErrMalformed, flag)
+		}
+	}
+
 	player := strings.TrimSpace(parts[3])
 	message := ""
 	if len(parts) == 5 {
-		message = strings.TrimSpace(parts[4])
+		if cmd == CmdJoin || cmd == CmdQuit {
+			// Join and quit lines carry no message, so a ';' belongs to the name.
+			player = strings.TrimSpace(parts[3] + ";" + parts[4])
+		} else {
+			message = strings.TrimSpace(parts[4])
+		}
 	}
 
 	return &PlayerEvent{
@@ -176,8 +652,9 @@ func parsePlayerEvent(line string, ts *time.Duration, raw string) (*PlayerEvent,
 }
 
 func parseChatPlayerEvent(line string, ts *time.Duration, raw string) (*PlayerEvent, error) {
+	// An empty say ("say <player>") does occur and leaves Message empty.
 	fields := strings.Fields(line)
-	if len(fields) < 3 {
+	if len(fields) < 2 {
 		return nil, fmt.Errorf("invalid chat play event line: %q", line)
 	}
 
@@ -198,6 +675,54 @@ func parseChatPlayerEvent(line string, ts *time.Duration, raw string) (*PlayerEv
 	}, nil
 }
 
+func parseTellEvent(line string, ts *time.Duration, raw string, base int) (*PlayerEvent, error) {
+	if strings.HasPrefix(line, "tell;") {
+		parts := strings.SplitN(line, ";", 6)
+		if len(parts) < 5 {
+			return nil, fmt.Errorf("invalid tell event line: %q", line)
+		}
+
+		flag, err := parseClientNum(strings.TrimSpace(parts[2]), base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flag %q: %w", parts[2], err)
+		}
+
+		message := ""
+		if len(parts) == 6 {
+			message = strings.TrimSpace(parts[5])
+		}
+
+		return &PlayerEvent{
+			BaseEvent: BaseEvent{
+				Timestamp: ts,
+				Command:   CmdTell,
+				Raw:       raw,
+			},
+			XUID:      strings.TrimSpace(parts[1]),
+			Flag:      flag,
+			Player:    strings.TrimSpace(parts[3]),
+			Message:   message,
+			Recipient: strings.TrimSpace(parts[4]),
+		}, nil
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid tell event line: %q", line)
+	}
+
+	return &PlayerEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   fields[0],
+			Raw:       raw,
+		},
+		Player:    fields[1],
+		Recipient: fields[2],
+		Message:   strings.Join(fields[3:], " "),
+	}, nil
+}
+
 func parseKeyValuePairs(s string) map[string]string {
 	data := make(map[string]string)
 	s = strings.TrimSpace(s)
@@ -213,27 +738,93 @@ func parseKeyValuePairs(s string) map[string]string {
 	return data
 }
 
+// tabFieldCounts holds the minimum and maximum field counts of the
+// semicolon-separated line shapes, keyed by command.
+var tabFieldCounts = map[string][2]int{
+	CmdKill:       {13, 14},
+	CmdDamage:     {13, 14},
+	CmdJoin:       {4, 4},
+	CmdQuit:       {4, 4},
+	CmdSay:        {5, 5},
+	CmdSayTeam:    {5, 5},
+	CmdTell:       {5, 6},
+	CmdWeapon:     {4, 5},
+	CmdItemPickup: {4, 5},
+}
+
+// sniffTabFields rewrites a tab-separated line to the semicolon-separated
+// form when its first field is a known command and it has at least as many
+// fields as that command's line shape. Tabs beyond the shape's last field are
+// kept, so a chat message may contain tabs. Lines that already contain a ';'
+// are never sniffed, so a tab-separated line whose name or message holds a
+// ';' is not recognized.
+func sniffTabFields(line string) string {
+	cmd, _, _ := strings.Cut(line, "\t")
+	counts, ok := tabFieldCounts[cmd]
+	if !ok {
+		if _, objective := lookupObjectiveCommand(cmd); !objective {
+			return line
+		}
+		counts = [2]int{4, 4}
+	}
+
+	if strings.Count(line, "\t")+1 < counts[0] {
+		return line
+	}
+	return strings.Replace(line, "\t", ";", counts[1]-1)
+}
+
+// splitTimestamp splits a leading timestamp off line. Only lines starting
+// with a digit are split into fields, and the rest of a timestamped line has
+// its whitespace collapsed to single spaces as it always had.
+func splitTimestamp(line string, format TimestampFormat) (*time.Duration, string) {
+	if line == "" || line[0] < '0' || line[0] > '9' {
+		return nil, line
+	}
+
+	end := strings.IndexAny(line, " \t")
+	if end < 0 {
+		return nil, line
+	}
+
+	first := line[:end]
+	switch colons := strings.Count(first, ":"); {
+	case colons == 0:
+		return nil, line
+	case format == TimestampMinutes && colons != 1, format == TimestampHours && colons != 2:
+		return nil, line
+	}
+
+	dur, err := parseTimestamp(first)
+	if err != nil {
+		return nil, line
+	}
+	rest := strings.Fields(line[end:])
+	if len(rest) == 0 {
+		return nil, line
+	}
+	return &dur, strings.Join(rest, " ")
+}
+
 func parseTimestamp(s string) (time.Duration, error) {
-	parts := strings.Split(s, ":")
-	if len(parts) < 2 || len(parts) > 3 {
+	colons := strings.Count(s, ":")
+	if colons < 1 || colons > 2 {
 		return 0, fmt.Errorf("invalid timestamp: %q", s)
 	}
 
-	nums := make([]int, len(parts))
-	for i, p := range parts {
-		n, err := strconv.Atoi(p)
+	totalSec := 0
+	rest := s
+	for i := 0; i <= colons; i++ {
+		part := rest
+		if j := strings.IndexByte(rest, ':'); j >= 0 {
+			part, rest = rest[:j], rest[j+1:]
+		}
+
+		n, err := strconv.Atoi(part)
 		if err != nil {
 			return 0, err
 		}
-		nums[i] = n
-	}
-
-	var totalSec int
-	switch len(nums) {
-	case 2:
-		totalSec = nums[0]*60 + nums[1]
-	case 3:
-		totalSec = nums[0]*3600 + nums[1]*60 + nums[2]
+		totalSec = totalSec*60 + n
 	}
 
 	return time.Duration(totalSec) * time.Second, nil
@@ -0,0 +1,541 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientNumOf returns the acting client number of the event types that carry one.
+func clientNumOf(t *testing.T, e Event) int {
+	t.Helper()
+	switch e := e.(type) {
+	case *JoinEvent:
+		return e.ClientNum
+	case *PlayerEvent:
+		return e.Flag
+	case *KillEvent:
+		return e.AttackerClientNum
+	case *ObjectiveEvent:
+		return e.ClientNum
+	case *VoiceEvent:
+		return e.ClientNum
+	case *SpawnEvent:
+		return e.ClientNum
+	case *WeaponChangeEvent:
+		return e.ClientNum
+	case *ItemEvent:
+		return e.ClientNum
+	}
+	t.Fatalf("unexpected event %T", e)
+	return 0
+}
+
+func TestParseClientNumBase(t *testing.T) {
+	opts := ParseOptions{ClientNumBase: 16}
+	lines := []string{
+		"J;aa;0x0a;Alice",
+		"Q;aa;0a;Alice",
+		"say;aa;0a;Alice;hi",
+		"tell;aa;0x0a;Alice;Bob;hi",
+		"K;bb;0a;allies;Bob;aa;0a;axis;Alice;ak47_mp;100;MOD_RIFLE_BULLET;head",
+		"BP;aa;0a;Alice",
+		"vsay;aa;0a;Alice;need_backup",
+		"ClientSpawn: 0a",
+		"Spawn;aa;0a;axis;Alice",
+		"Weapon;aa;0a;Alice;ak47_mp",
+		"ItemPickup;aa;0a;Alice;health",
+	}
+	for _, line := range lines {
+		e, err := ParseEventLineWithOptions(line, opts)
+		if err != nil {
+			t.Errorf("%q: %v", line, err)
+			continue
+		}
+		if got := clientNumOf(t, e); got != 10 {
+			t.Errorf("%q: client number %d, want 10", line, got)
+		}
+	}
+}
+
+func TestParseClientNumDefaultBaseRejectsHex(t *testing.T) {
+	for _, line := range []string{"tell;aa;0a;Alice;Bob;hi", "BP;aa;0a;Alice", "ClientSpawn: 0a"} {
+		e, err := ParseEventLine(line)
+		if err == nil {
+			if _, ok := e.(*BaseEvent); !ok {
+				t.Errorf("%q parsed as %T in base 10", line, e)
+			}
+		}
+	}
+}
+
+func TestSplitTimestampCollapsesWhitespace(t *testing.T) {
+	tests := []struct {
+		line string
+		ts   time.Duration
+		rest string
+	}{
+		{"12:34 J;111;3;A  B", 12*time.Minute + 34*time.Second, "J;111;3;A B"},
+		{"1:02:03\tsay;aa;1;Alice;a \t b", time.Hour + 2*time.Minute + 3*time.Second, "say;aa;1;Alice;a b"},
+		{"0:00  InitGame: \\g_gametype\\dm", 0, "InitGame: \\g_gametype\\dm"},
+	}
+	for _, tt := range tests {
+		ts, rest := splitTimestamp(tt.line, TimestampAuto)
+		if ts == nil || *ts != tt.ts || rest != tt.rest {
+			t.Errorf("splitTimestamp(%q) = %v, %q; want %v, %q", tt.line, ts, rest, tt.ts, tt.rest)
+		}
+	}
+
+	for _, line := range []string{"J;111;3;A  B", "12:34", "1234 J;111;3;A"} {
+		if ts, rest := splitTimestamp(line, TimestampAuto); ts != nil || rest != line {
+			t.Errorf("splitTimestamp(%q) = %v, %q; want no timestamp", line, ts, rest)
+		}
+	}
+
+	e, err := ParseEventLine("12:34 J;111;3;A  B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j, ok := e.(*JoinEvent); !ok || j.Name != "A B" {
+		t.Errorf("got %#v, want a join by %q", e, "A B")
+	}
+}
+
+// splitTimestampFields is the split used before the digit fast path, kept
+// to benchmark against.
+func splitTimestampFields(line string) (*time.Duration, string) {
+	fields := strings.Fields(line)
+	if len(fields) > 1 && strings.Contains(fields[0], ":") {
+		if dur, err := parseTimestamp(fields[0]); err == nil {
+			return &dur, strings.Join(fields[1:], " ")
+		}
+	}
+	return nil, line
+}
+
+var benchLines = map[string]string{
+	"timed":   "12:34 K;bb;1;allies;Bob;aa;0;axis;Alice;ak47_mp;100;MOD_RIFLE_BULLET;head",
+	"untimed": "K;bb;1;allies;Bob;aa;0;axis;Alice;ak47_mp;100;MOD_RIFLE_BULLET;head",
+}
+
+func BenchmarkSplitTimestamp(b *testing.B) {
+	for name, line := range benchLines {
+		b.Run(name+"/fields", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				splitTimestampFields(line)
+			}
+		})
+		b.Run(name+"/fast", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				splitTimestamp(line, TimestampAuto)
+			}
+		})
+	}
+}
+
+const killLine = "K;aa;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head"
+
+func parseKill(t *testing.T, line string) *KillEvent {
+	t.Helper()
+	e, err := ParseEventLine(line)
+	if err != nil {
+		t.Fatalf("%q: %v", line, err)
+	}
+	k, ok := e.(*KillEvent)
+	if !ok {
+		t.Fatalf("%q parsed as %T", line, e)
+	}
+	return k
+}
+
+func TestParseKillDistance(t *testing.T) {
+	if k := parseKill(t, killLine); k.Distance != nil {
+		t.Errorf("13-field line: Distance = %v, want nil", *k.Distance)
+	}
+	if k := parseKill(t, killLine+";12.5"); k.Distance == nil || *k.Distance != 12.5 {
+		t.Errorf("Distance = %v, want 12.5", k.Distance)
+	}
+	k := parseKill(t, killLine+";far")
+	if k.Distance != nil {
+		t.Errorf("non-numeric distance: Distance = %v, want nil", *k.Distance)
+	}
+	if k.HitLocation != "head" || k.VictimName != "Bob" {
+		t.Errorf("trailing field shifted the line: %+v", k)
+	}
+}
+
+func TestParseTell(t *testing.T) {
+	tests := []struct {
+		line                                string
+		xuid                                string
+		flag                                int
+		player, recipient, message, command string
+	}{
+		{"tell;aa;3;Alice;5;meet at B; now", "aa", 3, "Alice", "5", "meet at B; now", CmdTell},
+		{"tell;aa;3;Alice;5", "aa", 3, "Alice", "5", "", CmdTell},
+		{"0:42 tell Alice Bob hello  there", "", 0, "Alice", "Bob", "hello there", CmdTell},
+	}
+	for _, tt := range tests {
+		e, err := ParseEventLine(tt.line)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.line, err)
+		}
+		p, ok := e.(*PlayerEvent)
+		if !ok {
+			t.Fatalf("%q parsed as %T", tt.line, e)
+		}
+		if p.Command != tt.command || p.XUID != tt.xuid || p.Flag != tt.flag || p.Player != tt.player ||
+			p.Recipient != tt.recipient || p.Message != tt.message {
+			t.Errorf("%q parsed as %+v", tt.line, p)
+		}
+	}
+
+	if _, err := ParseEventLine("tell;aa;3;Alice"); err == nil {
+		t.Error("short tell line parsed")
+	}
+}
+
+// parseTimestampSplit is parseTimestamp as it was with strings.Split, kept to
+// check and benchmark against.
+func parseTimestampSplit(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", s)
+	}
+	totalSec := 0
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, err
+		}
+		totalSec = totalSec*60 + n
+	}
+	return time.Duration(totalSec) * time.Second, nil
+}
+
+func TestParseTimestampMatchesSplit(t *testing.T) {
+	for _, s := range []string{"0:00", "12:34", "1:02:03", "123:45", "1:2:3:4", "12", "", ":", "1:", "a:b", "-1:30", "1:-30"} {
+		got, gotErr := parseTimestamp(s)
+		want, wantErr := parseTimestampSplit(s)
+		if got != want || (gotErr == nil) != (wantErr == nil) {
+			t.Errorf("parseTimestamp(%q) = %v, %v; want %v, %v", s, got, gotErr, want, wantErr)
+		}
+	}
+}
+
+func BenchmarkParseTimestamp(b *testing.B) {
+	for _, s := range []string{"12:34", "1:02:03"} {
+		b.Run(s+"/split", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				parseTimestampSplit(s)
+			}
+		})
+		b.Run(s+"/index", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				parseTimestamp(s)
+			}
+		})
+	}
+}
+
+func BenchmarkParseEventLine(b *testing.B) {
+	for name, line := range map[string]string{
+		"kill": "12:34 " + killLine,
+		"join": "12:34 J;0123456789abcdef;3;Alice",
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseEventLine(line); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseNamesWithSemicolons(t *testing.T) {
+	kills := []struct {
+		line, attacker, victim string
+	}{
+		{"K;aa;0;axis;A;li;ce;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head", "A;li;ce", "Bob"},
+		{"K;aa;0;axis;Alice;bb;1;allies;B;ob;ak47_mp;100;MOD_RIFLE_BULLET;head", "Alice", "B;ob"},
+		{"K;aa;0;axis;A;1;x;bb;1;allies;;B;ak47_mp;100;MOD_RIFLE_BULLET;head;12.5", "A;1;x", ";B"},
+	}
+	for _, tt := range kills {
+		k := parseKill(t, tt.line)
+		if k.AttackerName != tt.attacker || k.VictimName != tt.victim {
+			t.Errorf("%q: names %q and %q, want %q and %q", tt.line, k.AttackerName, k.VictimName, tt.attacker, tt.victim)
+		}
+		if k.VictimXUID != "bb" || k.VictimClientNum != 1 || k.Weapon != "ak47_mp" || k.HitLocation != "head" {
+			t.Errorf("%q: fields shifted: %+v", tt.line, k)
+		}
+	}
+
+	e, err := ParseEventLine("J;aa;3;A;B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j, ok := e.(*JoinEvent); !ok || j.Name != "A;B" {
+		t.Errorf("join: got %#v", e)
+	}
+
+	e, err = ParseEventLine("Q;aa;3;A;B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, ok := e.(*PlayerEvent); !ok || q.Player != "A;B" || q.Flag != 3 {
+		t.Errorf("quit: got %#v", e)
+	}
+}
+
+func TestParseKillExtraFields(t *testing.T) {
+	k := parseKill(t, killLine+";15.5;time=12;lifespan = 3400")
+	want := map[string]string{"time": "12", "lifespan": "3400"}
+	if !reflect.DeepEqual(k.Extra, want) {
+		t.Errorf("Extra = %v, want %v", k.Extra, want)
+	}
+	if k.Distance == nil || *k.Distance != 15.5 {
+		t.Errorf("Distance = %v, want 15.5", k.Distance)
+	}
+
+	if k := parseKill(t, killLine); k.Extra != nil {
+		t.Errorf("Extra = %v for a plain line", k.Extra)
+	}
+	// A name containing '=' is not a trailing field.
+	k = parseKill(t, "K;aa;0;axis;a=b;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head")
+	if k.AttackerName != "a=b" || k.Extra != nil {
+		t.Errorf("got name %q, extra %v", k.AttackerName, k.Extra)
+	}
+}
+
+func TestParseEmptyChat(t *testing.T) {
+	tests := []struct {
+		line, command, player, message string
+	}{
+		{"say Alice", CmdSay, "Alice", ""},
+		{"12:00 sayteam Alice", CmdSayTeam, "Alice", ""},
+		{"say Alice hello there", CmdSay, "Alice", "hello there"},
+		{"say;aa;1;Alice;", CmdSay, "Alice", ""},
+	}
+	for _, tt := range tests {
+		e, err := ParseEventLine(tt.line)
+		if err != nil {
+			t.Errorf("%q: %v", tt.line, err)
+			continue
+		}
+		p, ok := e.(*PlayerEvent)
+		if !ok || p.Command != tt.command || p.Player != tt.player || p.Message != tt.message {
+			t.Errorf("%q parsed as %#v", tt.line, e)
+		}
+	}
+
+	if _, err := ParseEventLine("say "); err != nil {
+		t.Errorf("bare say: %v", err)
+	}
+}
+
+func TestParseKillArmorAndShield(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		line          string
+		distance      *float64
+		armor, shield *int
+	}{
+		{killLine, nil, nil, nil},
+		{killLine + ";40;25", nil, intPtr(40), intPtr(25)},
+		{killLine + ";8.25;40;25", func() *float64 { d := 8.25; return &d }(), intPtr(40), intPtr(25)},
+		{killLine + ";;25", nil, nil, intPtr(25)},
+	}
+	for _, tt := range tests {
+		for _, cmd := range []string{"K", "D"} {
+			line := cmd + tt.line[1:]
+			e, err := ParseEventLine(line)
+			if err != nil {
+				t.Fatalf("%q: %v", line, err)
+			}
+			var k *KillEvent
+			switch e := e.(type) {
+			case *KillEvent:
+				k = e
+			case *DamageEvent:
+				k = &e.KillEvent
+			default:
+				t.Fatalf("%q parsed as %T", line, e)
+			}
+			if !reflect.DeepEqual(k.Distance, tt.distance) || !reflect.DeepEqual(k.ArmorDamage, tt.armor) || !reflect.DeepEqual(k.ShieldDamage, tt.shield) {
+				t.Errorf("%q: distance %v armor %v shield %v", line, k.Distance, k.ArmorDamage, k.ShieldDamage)
+			}
+			if k.HitLocation != "head" {
+				t.Errorf("%q: HitLocation = %q", line, k.HitLocation)
+			}
+		}
+	}
+}
+
+func TestParseStripLogLevel(t *testing.T) {
+	opts := ParseOptions{StripLogLevel: true}
+	for _, line := range []string{
+		"INFO: " + killLine,
+		"info:" + killLine,
+		"[WARN] " + killLine,
+		"[Error]: " + killLine,
+		"[ DEBUG ] " + killLine,
+	} {
+		e, err := ParseEventLineWithOptions(line, opts)
+		if err != nil {
+			t.Errorf("%q: %v", line, err)
+			continue
+		}
+		k, ok := e.(*KillEvent)
+		if !ok {
+			t.Errorf("%q parsed as %T", line, e)
+			continue
+		}
+		if k.Raw != line {
+			t.Errorf("%q: Raw = %q", line, k.Raw)
+		}
+	}
+
+	e, err := ParseEventLineWithOptions("INFO: 12:34 "+killLine, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts := e.GetTimestamp(); ts == nil || *ts != 12*time.Minute+34*time.Second {
+		t.Errorf("timestamp after level = %v", ts)
+	}
+
+	if e, err := ParseEventLine("INFO: " + killLine); err == nil {
+		if _, ok := e.(*KillEvent); ok {
+			t.Error("level stripped without StripLogLevel")
+		}
+	}
+}
+
+func TestParseStripLogLevelCustomLevels(t *testing.T) {
+	opts := ParseOptions{StripLogLevel: true, LogLevels: []string{"game"}}
+	if _, ok := mustParse(t, "[GAME] "+killLine, opts).(*KillEvent); !ok {
+		t.Error("custom level not stripped")
+	}
+	if _, ok := mustParse(t, "INFO: "+killLine, opts).(*KillEvent); ok {
+		t.Error("default level stripped although LogLevels replaces them")
+	}
+}
+
+func TestStripLogLevelLeavesOtherTokens(t *testing.T) {
+	for _, line := range []string{
+		"InitGame: \\g_gametype\\dm",
+		"ShutdownGame:",
+		"[clan] Alice",
+		"[INFO without a close",
+		"K;aa;0;axis;Alice",
+	} {
+		if got := stripLogLevel(line, nil); got != line {
+			t.Errorf("stripLogLevel(%q) = %q", line, got)
+		}
+	}
+}
+
+func mustParse(t *testing.T, line string, opts ParseOptions) Event {
+	t.Helper()
+	e, err := ParseEventLineWithOptions(line, opts)
+	if err != nil {
+		t.Fatalf("%q: %v", line, err)
+	}
+	return e
+}
+
+func TestParseShutdownGameForms(t *testing.T) {
+	for _, line := range []string{
+		"ShutdownGame:",
+		"ShutdownGame",
+		"12:34 ShutdownGame",
+		"------ ShutdownGame ------",
+		"--ShutdownGame--",
+	} {
+		s, ok := mustParse(t, line, ParseOptions{}).(*ServerEvent)
+		if !ok || s.Command != CmdShutdownGame {
+			t.Errorf("%q not parsed as ShutdownGame", line)
+		}
+	}
+	for _, line := range []string{"ShutdownGames", "ShutdownGame now", "- Shutdown -"} {
+		if isShutdownGameLine(line) {
+			t.Errorf("%q taken for ShutdownGame", line)
+		}
+	}
+}
+
+func TestParseJoinTeam(t *testing.T) {
+	tests := []struct {
+		line, name, team string
+	}{
+		{"J;aa;3;Alice", "Alice", ""},
+		{"J;aa;3;Alice;axis", "Alice", "axis"},
+		{"J;aa;3;Alice;Allies", "Alice", "Allies"},
+		{"J;aa;3;Alice;spec", "Alice", "spec"},
+		{"J;aa;3;A;B;allies", "A;B", "allies"},
+		{"J;aa;3;A;B", "A;B", ""},
+		{"J;aa;3;Alice;world", "Alice;world", ""},
+		{"J;aa;3;Alice;", "Alice;", ""},
+		{"J;not-a-guid;3;Alice;axis", "Alice", "axis"},
+	}
+	for _, tt := range tests {
+		j, ok := mustParse(t, tt.line, ParseOptions{}).(*JoinEvent)
+		if !ok {
+			t.Errorf("%q not parsed as a join", tt.line)
+			continue
+		}
+		if j.Name != tt.name || j.Team != tt.team || j.ClientNum != 3 {
+			t.Errorf("%q: name %q, team %q, client %d; want %q, %q, 3", tt.line, j.Name, j.Team, j.ClientNum, tt.name, tt.team)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	strict := ParseOptions{Strict: true}
+	malformed := []string{
+		"K;aa;0;axis;Alice;bb;1;allies",
+		"K;zz!;0;axis;Alice;bb;1;allies;Bob;ak47_mp;100;MOD_RIFLE_BULLET",
+		"D;aa;0;axis;Alice;bb;1",
+		"J;zz!;3;Alice",
+		"J;aa;300;Alice",
+		"Q;aa;-1;Alice",
+	}
+	for _, line := range malformed {
+		if e, err := ParseEventLineWithOptions(line, strict); !errors.Is(err, ErrMalformed) {
+			t.Errorf("strict %q: got %#v, %v; want ErrMalformed", line, e, err)
+		}
+	}
+
+	// Lenient parsing keeps truncated kill and damage lines as unrecognized
+	// lines rather than misreading them as player lines.
+	for _, line := range malformed[:3] {
+		e, err := ParseEventLine(line)
+		if err != nil {
+			t.Errorf("lenient %q: %v", line, err)
+			continue
+		}
+		if b, ok := e.(*BaseEvent); !ok || b.Raw != line {
+			t.Errorf("lenient %q parsed as %#v, want a *BaseEvent", line, e)
+		}
+	}
+	for _, line := range malformed[3:] {
+		if _, err := ParseEventLine(line); err != nil {
+			t.Errorf("lenient %q: %v", line, err)
+		}
+	}
+
+	// Well-formed lines parse the same either way.
+	for _, line := range []string{killLine, "D" + killLine[1:], "J;aa;3;Alice", "Q;bot1;3;Bot"} {
+		want := mustParse(t, line, ParseOptions{})
+		if got := mustParse(t, line, strict); !reflect.DeepEqual(got, want) {
+			t.Errorf("strict %q = %#v, want %#v", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// PipelineBuilder configures a Pipeline that tails a file, preprocesses and
+// filters its lines and events, and dispatches what is left:
+//
+//	p, err := events.NewPipeline().
+//		FromFile("games_mp.log").
+//		Filter(func(e events.Event) bool { return e.GetCommand() == events.CmdKill }).
+//		Dispatch(d).
+//		Build()
+//
+// Each method returns the builder so that calls can be chained.
+type PipelineBuilder struct {
+	path       string
+	opts       TailOptions
+	preprocess []func(string) (string, bool)
+	filters    []func(Event) bool
+	dispatcher *Dispatcher
+	stats      *TailStats
+}
+
+func NewPipeline() *PipelineBuilder {
+	return &PipelineBuilder{}
+}
+
+// FromFile sets the file to tail.
+func (b *PipelineBuilder) FromFile(path string) *PipelineBuilder {
+	b.path = path
+	return b
+}
+
+// WithOptions sets the tail options. Preprocess and WithStats add to them
+// regardless of the order of the calls.
+func (b *PipelineBuilder) WithOptions(opts TailOptions) *PipelineBuilder {
+	b.opts = opts
+	return b
+}
+
+// Preprocess adds a line preprocessor, run after the options' PreprocessLine
+// and earlier preprocessors. A line dropped by one is not seen by the next.
+func (b *PipelineBuilder) Preprocess(fn func(string) (string, bool)) *PipelineBuilder {
+	b.preprocess = append(b.preprocess, fn)
+	return b
+}
+
+// Filter adds a predicate; only events all predicates keep are dispatched.
+func (b *PipelineBuilder) Filter(keep func(Event) bool) *PipelineBuilder {
+	b.filters = append(b.filters, keep)
+	return b
+}
+
+// Dispatch sets the dispatcher the events are handed to.
+func (b *PipelineBuilder) Dispatch(d *Dispatcher) *PipelineBuilder {
+	b.dispatcher = d
+	return b
+}
+
+// WithStats sets the TailStats the tailer updates.
+func (b *PipelineBuilder) WithStats(s *TailStats) *PipelineBuilder {
+	b.stats = s
+	return b
+}
+
+// Build checks the configuration and returns the pipeline. It fails if no
+// file or no dispatcher was set, or if a preprocessor or filter is nil.
+func (b *PipelineBuilder) Build() (*Pipeline, error) {
+	if b.path == "" {
+		return nil, errors.New("pipeline has no source: call FromFile")
+	}
+	if b.dispatcher == nil {
+		return nil, errors.New("pipeline has no dispatcher: call Dispatch")
+	}
+	for _, fn := range b.preprocess {
+		if fn == nil {
+			return nil, errors.New("pipeline has a nil preprocessor")
+		}
+	}
+	for _, keep := range b.filters {
+		if keep == nil {
+			return nil, errors.New("pipeline has a nil filter")
+		}
+	}
+
+	opts := b.opts
+	if b.stats != nil {
+		opts.Stats = b.stats
+	}
+	if len(b.preprocess) > 0 {
+		fns := append([]func(string) (string, bool)(nil), b.preprocess...)
+		if opts.PreprocessLine != nil {
+			fns = append([]func(string) (string, bool){opts.PreprocessLine}, fns...)
+		}
+		opts.PreprocessLine = func(line string) (string, bool) {
+			for _, fn := range fns {
+				var ok bool
+				if line, ok = fn(line); !ok {
+					return "", false
+				}
+			}
+			return line, true
+		}
+	}
+
+	return &Pipeline{
+		path:       b.path,
+		opts:       opts,
+		filters:    append([]func(Event) bool(nil), b.filters...),
+		dispatcher: b.dispatcher,
+	}, nil
+}
+
+// Pipeline is a configured tail-filter-dispatch chain; see PipelineBuilder.
+type Pipeline struct {
+	path       string
+	opts       TailOptions
+	filters    []func(Event) bool
+	dispatcher *Dispatcher
+}
+
+// Run tails the file and dispatches its events until ctx is cancelled or
+// the tail ends. It returns the tail's error if it failed, ctx.Err() if ctx
+// was cancelled, and nil otherwise. Handler errors are logged as by
+// Dispatcher.Run.
+func (p *Pipeline) Run(ctx context.Context) error {
+	eventsCh, stop, err := StartTailer(ctx, p.path, p.opts)
+	if err != nil {
+		return err
+	}
+
+	in := eventsCh
+	for _, keep := range p.filters {
+		in = Filter(ctx, in, keep)
+	}
+
+	runErr := p.dispatcher.Run(ctx, in)
+	if err := stop(); err != nil {
+		return err
+	}
+	return runErr
+}
@@ -4,14 +4,65 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
 const defaultPlayerCacheExpiry = 2 * time.Second
 
+// NameCollation selects how player names are compared by FindByName, and
+// chat messages by CoalesceChatWithOptions.
+type NameCollation int
+
+const (
+	// CollationSimple lowercases names with strings.ToLower.
+	CollationSimple NameCollation = iota
+	// CollationFold maps each rune to upper and then to lower case. Unlike
+	// strings.ToLower this also folds runes that only share an upper case,
+	// such as the Turkish dotless "ı", the long "ſ" and the final "ς", so
+	// "ı" and "İ" both match "i" and "I".
+	CollationFold
+)
+
+type DirectoryOptions struct {
+	TTL       time.Duration
+	Collation NameCollation
+
+	// CaseSensitiveGUID compares GUIDs exactly. By default GUIDs are
+	// compared case-insensitively, which suits hex GUIDs.
+	CaseSensitiveGUID bool
+
+	// ExcludeBots makes the Find* lookups skip players for which IsBot
+	// reports true. Snapshot always returns the full roster. Bots are
+	// included by default.
+	ExcludeBots bool
+
+	// OnSnapshot, if set, is called after every successful Snapshot, outside
+	// the directory's lock, with whether the source was queried and the
+	// number of players returned.
+	OnSnapshot func(refreshed bool, count int)
+}
+
 type Player struct {
 	ClientNum int
 	Name      string
 	GUID      string
+
+	// Address is the player's "ip:port" as reported by the source, if known.
+	Address string
+
+	// Team is the player's team as reported by the source or last seen in a
+	// kill applied with ApplyEvent.
+	Team Team
+
+	// Score, Ping, LastMsg, QPort and Rate are the status table columns of
+	// the same name. They are zero when the source does not report them, the
+	// layout has no such column, or the value is not a number, such as the
+	// "CNCT" ping of a connecting player.
+	Score   int
+	Ping    int
+	LastMsg int
+	QPort   int
+	Rate    int
 }
 
 type PlayerSource interface {
@@ -19,28 +70,51 @@ type PlayerSource interface {
 }
 
 type PlayerDirectory struct {
-	source  PlayerSource
-	ttl     time.Duration
-	mu      sync.RWMutex
-	players []Player
-	expires time.Time
+	source            PlayerSource
+	clock             clock
+	ttl               time.Duration
+	collation         NameCollation
+	caseSensitiveGUID bool
+	excludeBots       bool
+	onSnapshot        func(refreshed bool, count int)
+	mu                sync.RWMutex
+	players           []Player
+	expires           time.Time
+	refreshed         time.Time
+	lastApplied       map[int]time.Duration
+	generation        uint64
 }
 
 func NewPlayerDirectory(source PlayerSource, ttl time.Duration) *PlayerDirectory {
+	return NewPlayerDirectoryWithOptions(source, DirectoryOptions{TTL: ttl})
+}
+
+func NewPlayerDirectoryWithOptions(source PlayerSource, opts DirectoryOptions) *PlayerDirectory {
+	ttl := opts.TTL
 	if ttl <= 0 {
 		ttl = defaultPlayerCacheExpiry
 	}
-	return &PlayerDirectory{source: source, ttl: ttl}
+	return &PlayerDirectory{
+		source:            source,
+		clock:             realClock{},
+		ttl:               ttl,
+		collation:         opts.Collation,
+		caseSensitiveGUID: opts.CaseSensitiveGUID,
+		excludeBots:       opts.ExcludeBots,
+		onSnapshot:        opts.OnSnapshot,
+	}
 }
 
 func (d *PlayerDirectory) Snapshot() ([]Player, error) {
 	d.mu.RLock()
-	if len(d.players) > 0 && time.Now().Before(d.expires) {
+	if len(d.players) > 0 && d.clock.Now().Before(d.expires) {
 		result := make([]Player, len(d.players))
 		copy(result, d.players)
 		d.mu.RUnlock()
+		d.notifySnapshot(false, len(result))
 		return result, nil
 	}
+	generation := d.generation
 	d.mu.RUnlock()
 
 	players, err := d.source.Status()
@@ -48,32 +122,43 @@ func (d *PlayerDirectory) Snapshot() ([]Player, error) {
 		return nil, err
 	}
 
+	// An Invalidate that ran while the source was being queried means this
+	// roster may predate it, so it is returned but not cached.
 	d.mu.Lock()
-	d.players = make([]Player, len(players))
-	copy(d.players, players)
-	d.expires = time.Now().Add(d.ttl)
+	if d.generation == generation {
+		d.players = make([]Player, len(players))
+		copy(d.players, players)
+		d.refreshed = d.clock.Now()
+		d.expires = d.refreshed.Add(d.ttl)
+	}
 	d.mu.Unlock()
 
 	result := make([]Player, len(players))
 	copy(result, players)
+	d.notifySnapshot(true, len(result))
 	return result, nil
 }
 
+func (d *PlayerDirectory) notifySnapshot(refreshed bool, count int) {
+	if d.onSnapshot != nil {
+		d.onSnapshot(refreshed, count)
+	}
+}
+
 func (d *PlayerDirectory) FindByName(name string) (*Player, error) {
-	name = strings.TrimSpace(stripColorCodes(name))
+	name = normalizeName(name, d.collation)
 	if name == "" {
 		return nil, nil
 	}
 
-	players, err := d.Snapshot()
+	players, err := d.lookupSnapshot()
 	if err != nil {
 		return nil, err
 	}
 
-	lower := strings.ToLower(name)
 	for _, p := range players {
-		candidate := strings.ToLower(stripColorCodes(p.Name))
-		if strings.Contains(candidate, lower) {
+		candidate := normalizeName(p.Name, d.collation)
+		if strings.Contains(candidate, name) {
 			player := p
 			return &player, nil
 		}
@@ -83,7 +168,7 @@ func (d *PlayerDirectory) FindByName(name string) (*Player, error) {
 }
 
 func (d *PlayerDirectory) FindByClientNum(clientNum int) (*Player, error) {
-	players, err := d.Snapshot()
+	players, err := d.lookupSnapshot()
 	if err != nil {
 		return nil, err
 	}
@@ -98,18 +183,18 @@ func (d *PlayerDirectory) FindByClientNum(clientNum int) (*Player, error) {
 }
 
 func (d *PlayerDirectory) FindByGUID(guid string) (*Player, error) {
-	guid = strings.TrimSpace(guid)
+	guid = d.normalizeGUID(guid)
 	if guid == "" {
 		return nil, nil
 	}
 
-	players, err := d.Snapshot()
+	players, err := d.lookupSnapshot()
 	if err != nil {
 		return nil, err
 	}
 
 	for _, p := range players {
-		if strings.EqualFold(strings.TrimSpace(p.GUID), guid) {
+		if d.normalizeGUID(p.GUID) == guid {
 			player := p
 			return &player, nil
 		}
@@ -117,13 +202,291 @@ func (d *PlayerDirectory) FindByGUID(guid string) (*Player, error) {
 	return nil, nil
 }
 
+// FindByAddress returns the players whose address has the same IP as addr,
+// ignoring ports. An empty or unparsable address matches nothing.
+func (d *PlayerDirectory) FindByAddress(addr string) ([]Player, error) {
+	host := addressHost(addr)
+	if host == "" {
+		return nil, nil
+	}
+
+	players, err := d.lookupSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Player
+	for _, p := range players {
+		if addressHost(p.Address) == host {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+// ResolveKill looks up the current players behind a kill's attacker and
+// victim, from a single snapshot. Each side is matched by GUID, then by client
+// number, then by exact name; the fallbacks skip players whose GUID differs
+// from the kill's. A side that is not found is nil, as is the attacker of a
+// world kill or suicide.
+func (d *PlayerDirectory) ResolveKill(k *KillEvent) (attacker, victim *Player, err error) {
+	players, err := d.lookupSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !k.IsWorldKill() && !k.IsSuicide() {
+		attacker = d.resolvePlayer(players, k.AttackerXUID, k.AttackerClientNum, k.AttackerName)
+	}
+	victim = d.resolvePlayer(players, k.VictimXUID, k.VictimClientNum, k.VictimName)
+	return attacker, victim, nil
+}
+
+func (d *PlayerDirectory) resolvePlayer(players []Player, guid string, clientNum int, name string) *Player {
+	guid = d.normalizeGUID(guid)
+	if guid != "" {
+		for _, p := range players {
+			if d.normalizeGUID(p.GUID) == guid {
+				player := p
+				return &player
+			}
+		}
+	}
+
+	// Past this point a player known under a different GUID is someone else.
+	var candidates []Player
+	for _, p := range players {
+		if guid == "" || p.GUID == "" {
+			candidates = append(candidates, p)
+		}
+	}
+
+	if clientNum >= 0 {
+		for _, p := range candidates {
+			if p.ClientNum == clientNum {
+				player := p
+				return &player
+			}
+		}
+	}
+
+	name = normalizeName(name, d.collation)
+	if name == "" {
+		return nil
+	}
+	for _, p := range candidates {
+		if normalizeName(p.Name, d.collation) == name {
+			player := p
+			return &player
+		}
+	}
+	return nil
+}
+
+func (d *PlayerDirectory) lookupSnapshot() ([]Player, error) {
+	players, err := d.Snapshot()
+	if err != nil || !d.excludeBots {
+		return players, err
+	}
+
+	humans := players[:0]
+	for _, p := range players {
+		if !IsBot(p.GUID) {
+			humans = append(humans, p)
+		}
+	}
+	return humans, nil
+}
+
+// ApplyEvent updates the cached roster from a join ("J") or quit ("Q") event
+// so that lookups reflect connects and disconnects between source refreshes,
+// from a NameChangeEvent, and from the teams in a kill. It reports whether the
+// event changed the cache.
+//
+// Events may arrive out of order, e.g. when merged from several sources. For
+// each client number the timestamp of the last applied event is remembered,
+// and a timestamped event older than that is ignored, so a late join cannot
+// resurrect a player who already left. Events without a timestamp always
+// apply, in arrival order. An InitGame clears the remembered timestamps since
+// the game clock restarts with each match.
+func (d *PlayerDirectory) ApplyEvent(e Event) bool {
+	joinTeam := TeamUnknown
+	if j, ok := e.(*JoinEvent); ok {
+		if j.Team != "" {
+			joinTeam = ParseTeam(j.Team)
+		}
+		e = j.AsPlayerEvent()
+	}
+
+	switch t := e.(type) {
+	case *ServerEvent:
+		if t.Command == CmdInitGame {
+			d.mu.Lock()
+			d.lastApplied = nil
+			d.mu.Unlock()
+		}
+		return false
+	case *NameChangeEvent:
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		for i := range d.players {
+			if d.players[i].ClientNum == t.ClientNum && d.players[i].Name != t.NewName {
+				d.players[i].Name = t.NewName
+				return true
+			}
+		}
+		return false
+	case *KillEvent:
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		changed := d.setTeamLocked(t.AttackerClientNum, t.AttackerTeam)
+		return d.setTeamLocked(t.VictimClientNum, t.VictimTeam) || changed
+	case *PlayerEvent:
+		if t.Command != CmdJoin && t.Command != CmdQuit || !validClientNum(t.Flag) {
+			return false
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if t.Timestamp != nil {
+			if last, ok := d.lastApplied[t.Flag]; ok && *t.Timestamp < last {
+				return false
+			}
+			if d.lastApplied == nil {
+				d.lastApplied = make(map[int]time.Duration)
+			}
+			d.lastApplied[t.Flag] = *t.Timestamp
+		}
+
+		// A join evicts whoever held the slot before, and the same player
+		// in another slot after a reconnect. A refresh already in flight may
+		// predate the event, so like Invalidate this stops it being cached.
+		d.removeClientLocked(t.Flag)
+		if t.Command == CmdJoin {
+			d.removeGUIDLocked(t.XUID)
+			d.players = append(d.players, Player{ClientNum: t.Flag, Name: t.Player, GUID: t.XUID, Team: joinTeam})
+		}
+		d.generation++
+		return true
+	}
+	return false
+}
+
+func (d *PlayerDirectory) setTeamLocked(clientNum int, token string) bool {
+	team := ParseTeam(token)
+	if team == TeamUnknown || team == TeamWorld {
+		return false
+	}
+	for i := range d.players {
+		if d.players[i].ClientNum == clientNum && d.players[i].Team != team {
+			d.players[i].Team = team
+			return true
+		}
+	}
+	return false
+}
+
+func (d *PlayerDirectory) removeClientLocked(clientNum int) {
+	kept := d.players[:0]
+	for _, p := range d.players {
+		if p.ClientNum != clientNum {
+			kept = append(kept, p)
+		}
+	}
+	d.players = kept
+}
+
+func (d *PlayerDirectory) removeGUIDLocked(guid string) {
+	guid = d.normalizeGUID(guid)
+	if guid == "" || guid == "0" {
+		return
+	}
+	kept := d.players[:0]
+	for _, p := range d.players {
+		if d.normalizeGUID(p.GUID) != guid {
+			kept = append(kept, p)
+		}
+	}
+	d.players = kept
+}
+
+func (d *PlayerDirectory) normalizeGUID(guid string) string {
+	guid = strings.TrimSpace(guid)
+	if d.caseSensitiveGUID {
+		return guid
+	}
+	return strings.ToLower(guid)
+}
+
 func (d *PlayerDirectory) Invalidate() {
 	d.mu.Lock()
 	d.players = nil
 	d.expires = time.Time{}
+	d.refreshed = time.Time{}
+	d.generation++
 	d.mu.Unlock()
 }
 
+// LastRefresh returns when the cached roster was last fetched from the
+// source, or the zero time if nothing is cached. It never triggers a refresh.
+func (d *PlayerDirectory) LastRefresh() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.refreshed
+}
+
+// SnapshotAge returns how long ago the cached roster was fetched, or -1 if
+// nothing is cached. It never triggers a refresh.
+func (d *PlayerDirectory) SnapshotAge() time.Duration {
+	refreshed := d.LastRefresh()
+	if refreshed.IsZero() {
+		return -1
+	}
+	return d.clock.Now().Sub(refreshed)
+}
+
+// IsBot reports whether guid is an engine bot GUID such as "bot3".
+func IsBot(guid string) bool {
+	guid = strings.TrimSpace(guid)
+	if len(guid) <= 3 || !strings.EqualFold(guid[:3], "bot") {
+		return false
+	}
+	for _, r := range guid[3:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeName(name string, collation NameCollation) string {
+	return collate(CleanName(name), collation)
+}
+
+func collate(s string, collation NameCollation) string {
+	if collation == CollationFold {
+		return strings.Map(foldRune, s)
+	}
+	return strings.ToLower(s)
+}
+
+// foldRune lowercases the upper case of r. It is not Unicode case folding,
+// which keeps "ı" and "İ" apart from "i", but folds more than unicode.ToLower.
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// CleanName returns name without "^N" color codes and surrounding spaces.
+// Parsed events keep names exactly as logged; use CleanName for display.
+func CleanName(name string) string {
+	return strings.TrimSpace(stripColorCodes(name))
+}
+
+func (p Player) CleanName() string { return CleanName(p.Name) }
+
 func stripColorCodes(input string) string {
 	if input == "" {
 		return ""
@@ -0,0 +1,350 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingSource returns a fixed roster and counts the calls to Status.
+type countingSource struct {
+	mu      sync.Mutex
+	players []Player
+	calls   int
+}
+
+func (s *countingSource) Status() ([]Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return append([]Player(nil), s.players...), nil
+}
+
+func (s *countingSource) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestPlayerDirectoryTTLExpiry(t *testing.T) {
+	src := &countingSource{players: []Player{{ClientNum: 0, Name: "Alice", GUID: "aa"}}}
+	clk := newFakeClock()
+	d := NewPlayerDirectoryWithOptions(src, DirectoryOptions{TTL: time.Second})
+	d.clock = clk
+
+	if age := d.SnapshotAge(); age != -1 {
+		t.Fatalf("SnapshotAge before first snapshot = %v, want -1", age)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := d.Snapshot(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := src.Calls(); got != 1 {
+		t.Fatalf("source queried %d times within TTL, want 1", got)
+	}
+
+	clk.Advance(999 * time.Millisecond)
+	if _, err := d.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if got := src.Calls(); got != 1 {
+		t.Fatalf("source queried %d times just before expiry, want 1", got)
+	}
+	if age := d.SnapshotAge(); age != 999*time.Millisecond {
+		t.Fatalf("SnapshotAge = %v, want 999ms", age)
+	}
+
+	clk.Advance(time.Millisecond)
+	if _, err := d.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if got := src.Calls(); got != 2 {
+		t.Fatalf("source queried %d times after expiry, want 2", got)
+	}
+	if age := d.SnapshotAge(); age != 0 {
+		t.Fatalf("SnapshotAge after refresh = %v, want 0", age)
+	}
+}
+
+type staticSource []Player
+
+func (s staticSource) Status() ([]Player, error) { return s, nil }
+
+func TestFindByNameTurkishFolding(t *testing.T) {
+	players := staticSource{
+		{ClientNum: 0, Name: "^1Işık", GUID: "aa"},
+		{ClientNum: 1, Name: "İSTANBUL", GUID: "bb"},
+	}
+	tests := []struct {
+		query string
+		want  string // GUID under CollationFold, "" for no match
+	}{
+		{"işik", "aa"},
+		{"IŞIK", "aa"},
+		{"ışık", "aa"},
+		{"istanbul", "bb"},
+		{"ıstanbul", "bb"},
+		{"tanb", "bb"},
+		{"ankara", ""},
+	}
+
+	fold := NewPlayerDirectoryWithOptions(players, DirectoryOptions{Collation: CollationFold})
+	for _, tt := range tests {
+		p, err := fold.FindByName(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if p != nil {
+			got = p.GUID
+		}
+		if got != tt.want {
+			t.Errorf("CollationFold FindByName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	// strings.ToLower keeps "ı" apart from "i".
+	simple := NewPlayerDirectory(players, time.Second)
+	for _, query := range []string{"ışık", "ıstanbul"} {
+		if p, _ := simple.FindByName(query); p != nil {
+			t.Errorf("CollationSimple FindByName(%q) matched %q", query, p.Name)
+		}
+	}
+	if p, _ := simple.FindByName("işık"); p == nil || p.GUID != "aa" {
+		t.Errorf("CollationSimple FindByName(%q) = %v, want aa", "işık", p)
+	}
+}
+
+func TestFoldRune(t *testing.T) {
+	for in, want := range map[string]string{"Iı": "ii", "İi": "ii", "ſ": "s", "ΣΑΣς": "σασσ", "ДРУГ": "друг"} {
+		if got := strings.Map(foldRune, in); got != want {
+			t.Errorf("fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// appliedDirectory returns a directory caching players, whose cache never
+// expires during the test.
+func appliedDirectory(t *testing.T, players ...Player) *PlayerDirectory {
+	t.Helper()
+	d := NewPlayerDirectory(staticSource(players), time.Hour)
+	d.clock = newFakeClock()
+	if _, err := d.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func applyLines(t *testing.T, d *PlayerDirectory, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		e, err := ParseEventLine(line)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		d.ApplyEvent(e)
+	}
+}
+
+func clientName(t *testing.T, d *PlayerDirectory, clientNum int) string {
+	t.Helper()
+	p, err := d.FindByClientNum(clientNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+func TestPlayerDirectoryApplyEventIgnoresStale(t *testing.T) {
+	d := appliedDirectory(t, Player{ClientNum: 0, Name: "Host", GUID: "hh"})
+
+	applyLines(t, d, "1:00 J;aa;3;Alice", "2:00 Q;aa;3;Alice", "1:30 J;aa;3;Alice")
+	if name := clientName(t, d, 3); name != "" {
+		t.Fatalf("late join brought back %q", name)
+	}
+
+	// Untimed events apply in arrival order.
+	applyLines(t, d, "J;aa;3;Alice")
+	if name := clientName(t, d, 3); name != "Alice" {
+		t.Fatalf("untimed join: client 3 is %q, want Alice", name)
+	}
+
+	// The game clock restarts with a new match.
+	applyLines(t, d, "2:30 Q;aa;3;Alice", "3:00 InitGame: \\g_gametype\\dm", "0:05 J;bb;3;Bob")
+	if name := clientName(t, d, 3); name != "Bob" {
+		t.Fatalf("after InitGame: client 3 is %q, want Bob", name)
+	}
+	if name := clientName(t, d, 0); name != "Host" {
+		t.Errorf("client 0 is %q, want Host", name)
+	}
+}
+
+// blockingSource blocks each Status call until release is closed.
+type blockingSource struct {
+	countingSource
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) Status() ([]Player, error) {
+	s.called <- struct{}{}
+	<-s.release
+	return s.countingSource.Status()
+}
+
+func TestPlayerDirectoryInvalidateDuringSnapshot(t *testing.T) {
+	src := &blockingSource{
+		countingSource: countingSource{players: []Player{{ClientNum: 0, Name: "Alice", GUID: "aa"}}},
+		called:         make(chan struct{}, 2),
+		release:        make(chan struct{}),
+	}
+	d := NewPlayerDirectory(src, time.Hour)
+
+	done := make(chan []Player)
+	go func() {
+		players, _ := d.Snapshot()
+		done <- players
+	}()
+	<-src.called
+	d.Invalidate()
+	close(src.release)
+
+	if players := <-done; len(players) != 1 {
+		t.Fatalf("Snapshot returned %v, want the fetched roster", players)
+	}
+	if !d.LastRefresh().IsZero() {
+		t.Error("roster fetched before Invalidate was cached")
+	}
+	if _, err := d.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if got := src.Calls(); got != 2 {
+		t.Errorf("source queried %d times, want 2", got)
+	}
+}
+
+func TestPlayerDirectoryConcurrentInvalidate(t *testing.T) {
+	src := &countingSource{players: []Player{{ClientNum: 0, Name: "Alice", GUID: "aa"}}}
+	d := NewPlayerDirectory(src, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				switch (i + j) % 4 {
+				case 0:
+					d.Invalidate()
+				case 1:
+					d.ApplyEvent(&JoinEvent{GUID: "bb", ClientNum: 1, Name: "Bob"})
+				default:
+					if players, err := d.Snapshot(); err != nil || len(players) == 0 {
+						t.Errorf("Snapshot = %v, %v", players, err)
+						return
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestPlayerDirectoryIgnoresOutOfRangeClients(t *testing.T) {
+	d := appliedDirectory(t, Player{ClientNum: 0, Name: "Host", GUID: "hh"})
+	for _, num := range []int{-1, 256, 1 << 20} {
+		if d.ApplyEvent(&JoinEvent{GUID: "aa", ClientNum: num, Name: "Alice"}) {
+			t.Errorf("join for client %d applied", num)
+		}
+	}
+	if p, _ := d.FindByGUID("aa"); p != nil {
+		t.Errorf("out-of-range join added %+v", p)
+	}
+}
+
+func TestPlayerDirectoryClientNumReuse(t *testing.T) {
+	d := appliedDirectory(t,
+		Player{ClientNum: 0, Name: "Alice", GUID: "aa"},
+		Player{ClientNum: 1, Name: "Bob", GUID: "bb"},
+	)
+
+	// A new player taking Bob's slot replaces him.
+	applyLines(t, d, "J;cc;1;Carl")
+	if name := clientName(t, d, 1); name != "Carl" {
+		t.Fatalf("client 1 is %q, want Carl", name)
+	}
+	if p, _ := d.FindByGUID("bb"); p != nil {
+		t.Errorf("Bob still listed after his slot was reused: %+v", p)
+	}
+
+	// Alice reconnecting into another slot leaves her old one.
+	applyLines(t, d, "J;aa;5;Alice")
+	if name := clientName(t, d, 0); name != "" {
+		t.Errorf("client 0 is %q after Alice reconnected as 5", name)
+	}
+	if p, _ := d.FindByGUID("aa"); p == nil || p.ClientNum != 5 {
+		t.Errorf("FindByGUID(aa) = %+v, want client 5", p)
+	}
+
+	players, err := d.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 2 {
+		t.Errorf("roster %+v, want Carl and Alice", players)
+	}
+}
+
+func TestPlayerDirectoryApplyEventStopsStaleRefresh(t *testing.T) {
+	src := &blockingSource{
+		countingSource: countingSource{players: []Player{{ClientNum: 1, Name: "Bob", GUID: "bb"}}},
+		called:         make(chan struct{}, 2),
+		release:        make(chan struct{}),
+	}
+	d := NewPlayerDirectory(src, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		d.Snapshot()
+		close(done)
+	}()
+	<-src.called
+	applyLines(t, d, "Q;bb;1;Bob")
+	close(src.release)
+	<-done
+
+	// The roster read before the quit was not cached.
+	if !d.LastRefresh().IsZero() {
+		t.Error("roster that predates the quit was cached")
+	}
+}
+
+func TestFindByNameExcludeBots(t *testing.T) {
+	players := staticSource{
+		{ClientNum: 0, Name: "[BOT]Alice", GUID: "bot0"},
+		{ClientNum: 1, Name: "Alice", GUID: "aa"},
+		{ClientNum: 2, Name: "Bob", GUID: "bot1"},
+	}
+
+	all := NewPlayerDirectoryWithOptions(players, DirectoryOptions{})
+	if p, _ := all.FindByName("alice"); p == nil || p.GUID != "bot0" {
+		t.Errorf("bots included: FindByName(alice) = %v, want bot0", p)
+	}
+
+	humans := NewPlayerDirectoryWithOptions(players, DirectoryOptions{ExcludeBots: true})
+	if p, _ := humans.FindByName("alice"); p == nil || p.GUID != "aa" {
+		t.Errorf("ExcludeBots: FindByName(alice) = %v, want aa", p)
+	}
+	if p, _ := humans.FindByName("bob"); p != nil {
+		t.Errorf("ExcludeBots: FindByName(bob) = %v, want no match", p)
+	}
+	if roster, _ := humans.Snapshot(); len(roster) != 3 {
+		t.Errorf("ExcludeBots: Snapshot returned %d players, want 3", len(roster))
+	}
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+)
+
+const defaultProbeSample = 100
+
+type ProbeResult struct {
+	Lines      int
+	Recognized int
+	Confidence float64
+	Commands   []string
+	Timestamps bool
+}
+
+// Probe reads up to sample non-blank lines from the start of path and reports
+// how many of them parsed into a recognized event type. Lines that only fall
+// through to a bare BaseEvent do not count as recognized. An unrecognizable
+// file yields a low Confidence rather than an error; errors are reserved for
+// failing to open or read the file.
+func Probe(path string, sample int) (ProbeResult, error) {
+	if sample <= 0 {
+		sample = defaultProbeSample
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ProbeResult{}, err
+	}
+	defer f.Close()
+
+	var res ProbeResult
+	seen := make(map[string]bool)
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for res.Lines < sample && sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		res.Lines++
+
+		ev, err := ParseEventLine(line)
+		if err != nil {
+			continue
+		}
+		if ev.GetTimestamp() != nil {
+			res.Timestamps = true
+		}
+		if _, bare := ev.(*BaseEvent); bare {
+			continue
+		}
+
+		res.Recognized++
+		if cmd := ev.GetCommand(); !seen[cmd] {
+			seen[cmd] = true
+			res.Commands = append(res.Commands, cmd)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return res, err
+	}
+
+	if res.Lines > 0 {
+		res.Confidence = float64(res.Recognized) / float64(res.Lines)
+	}
+	sort.Strings(res.Commands)
+	return res, nil
+}
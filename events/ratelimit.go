@@ -0,0 +1,37 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter allows one call per interval and counts the calls it rejected
+// in between. A non-positive interval allows every call.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// allow reports whether the call may proceed and, if so, how many calls were
+// suppressed since the last allowed one.
+func (l *rateLimiter) allow(now time.Time) (bool, int) {
+	if l.interval <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.Sub(l.last) < l.interval {
+		l.suppressed++
+		return false, 0
+	}
+
+	suppressed := l.suppressed
+	l.last = now
+	l.suppressed = 0
+	return true, suppressed
+}
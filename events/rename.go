@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"strings"
+)
+
+// CmdNameChange is the Command of NameChangeEvents. It never appears in logs;
+// the events are synthesized by DetectRenames.
+const CmdNameChange = "NameChange"
+
+// NameChangeEvent reports that a connected player changed name. The log has
+// no dedicated rename line: the engine re-issues the join with the new name,
+// so OldName can only be known by tracking earlier joins, which DetectRenames
+// does. Line parsing alone never produces this event.
+type NameChangeEvent struct {
+	BaseEvent
+	GUID      string
+	ClientNum int
+	OldName   string
+	NewName   string
+}
+
+// DetectRenames forwards the events from in and, after a join for a client
+// number already joined under the same GUID with a different name, emits a
+// NameChangeEvent carrying the join's timestamp and raw line. A quit forgets
+// the client.
+func DetectRenames(ctx context.Context, in <-chan Event) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		joined := make(map[int]*JoinEvent)
+
+		send := func(e Event) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- e:
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				if !send(e) {
+					return
+				}
+
+				switch t := e.(type) {
+				case *JoinEvent:
+					if !validClientNum(t.ClientNum) {
+						continue
+					}
+					prev, known := joined[t.ClientNum]
+					joined[t.ClientNum] = t
+					if !known || !strings.EqualFold(prev.GUID, t.GUID) || prev.Name == t.Name {
+						continue
+					}
+					rename := &NameChangeEvent{
+						BaseEvent: t.BaseEvent,
+						GUID:      t.GUID,
+						ClientNum: t.ClientNum,
+						OldName:   prev.Name,
+						NewName:   t.Name,
+					}
+					rename.Command = CmdNameChange
+					if !send(rename) {
+						return
+					}
+				case *PlayerEvent:
+					if t.Command == CmdQuit {
+						delete(joined, t.Flag)
+					}
+				}
+			}
+		}
+	}()
+
+	return out
+}
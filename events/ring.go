@@ -0,0 +1,117 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// RingBuffer keeps the most recent events in memory, overwriting the oldest
+// once full. It is safe for concurrent use.
+//
+// Game-clock timestamps restart with every match, so each event is tagged
+// with the epoch it was added in; the epoch advances on every InitGame.
+type RingBuffer struct {
+	mu    sync.Mutex
+	buf   []ringEntry
+	next  int
+	full  bool
+	epoch uint64
+}
+
+type ringEntry struct {
+	ev    Event
+	epoch uint64
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer{buf: make([]ringEntry, capacity)}
+}
+
+func (r *RingBuffer) Add(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if isInitGame(e) {
+		r.epoch++
+	}
+	r.buf[r.next] = ringEntry{ev: e, epoch: r.epoch}
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (r *RingBuffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.buf)
+	}
+	return r.next
+}
+
+// Epoch returns the current epoch, i.e. the number of InitGame events added.
+func (r *RingBuffer) Epoch() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.epoch
+}
+
+// Events returns the buffered events, oldest first.
+func (r *RingBuffer) Events() []Event {
+	var out []Event
+	r.each(func(en ringEntry) {
+		out = append(out, en.ev)
+	})
+	return out
+}
+
+// Between returns the events of the current epoch whose timestamp lies in
+// [from, to], oldest first. Events without a timestamp are excluded.
+func (r *RingBuffer) Between(from, to time.Duration) []Event {
+	return r.BetweenEpoch(r.Epoch(), from, to)
+}
+
+// BetweenEpoch is like Between for an earlier epoch still in the buffer.
+func (r *RingBuffer) BetweenEpoch(epoch uint64, from, to time.Duration) []Event {
+	var out []Event
+	r.each(func(en ringEntry) {
+		if en.epoch != epoch {
+			return
+		}
+		ts := en.ev.GetTimestamp()
+		if ts != nil && *ts >= from && *ts <= to {
+			out = append(out, en.ev)
+		}
+	})
+	return out
+}
+
+// Untimed returns the buffered events that carry no timestamp, oldest first.
+func (r *RingBuffer) Untimed() []Event {
+	var out []Event
+	r.each(func(en ringEntry) {
+		if en.ev.GetTimestamp() == nil {
+			out = append(out, en.ev)
+		}
+	})
+	return out
+}
+
+func (r *RingBuffer) each(fn func(ringEntry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.full {
+		for _, en := range r.buf[r.next:] {
+			fn(en)
+		}
+	}
+	for _, en := range r.buf[:r.next] {
+		fn(en)
+	}
+}
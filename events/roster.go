@@ -0,0 +1,56 @@
+package events
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DiffRosters compares two roster snapshots, such as successive results of
+// PlayerDirectory.Snapshot. Players are matched by GUID, compared
+// case-insensitively, or by client number when the GUID is empty or "0".
+// joined holds the players only in new and left those only in old; renamed
+// holds, as they appear in new, the matched players whose name changed.
+// Each result keeps the order of the snapshot it is taken from.
+func DiffRosters(old, new []Player) (joined, left, renamed []Player) {
+	before := make(map[string]Player, len(old))
+	for _, p := range old {
+		key := rosterKey(p)
+		if _, dup := before[key]; !dup {
+			before[key] = p
+		}
+	}
+
+	after := make(map[string]bool, len(new))
+	for _, p := range new {
+		key := rosterKey(p)
+		if after[key] {
+			continue
+		}
+		after[key] = true
+
+		prev, ok := before[key]
+		switch {
+		case !ok:
+			joined = append(joined, p)
+		case prev.Name != p.Name:
+			renamed = append(renamed, p)
+		}
+	}
+
+	for _, p := range old {
+		key := rosterKey(p)
+		if !after[key] {
+			after[key] = true
+			left = append(left, p)
+		}
+	}
+	return joined, left, renamed
+}
+
+func rosterKey(p Player) string {
+	guid := strings.TrimSpace(p.GUID)
+	if guid == "" || guid == "0" {
+		return "num:" + strconv.Itoa(p.ClientNum)
+	}
+	return "guid:" + strings.ToLower(guid)
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRosters(t *testing.T) {
+	old := []Player{
+		{ClientNum: 0, GUID: "AA", Name: "Alice"},
+		{ClientNum: 1, GUID: "bb", Name: "Bob"},
+		{ClientNum: 2, GUID: "0", Name: "Local"},
+		{ClientNum: 3, GUID: "dd", Name: "Dave"},
+	}
+	new := []Player{
+		{ClientNum: 4, GUID: "ee", Name: "Eve"},
+		{ClientNum: 0, GUID: "aa", Name: "Alice"},
+		{ClientNum: 2, GUID: "", Name: "Local2"},
+		{ClientNum: 5, GUID: "bb", Name: "Bobby"},
+		{ClientNum: 5, GUID: "bb", Name: "Bobby"},
+	}
+
+	joined, left, renamed := DiffRosters(old, new)
+	if want := []Player{new[0]}; !reflect.DeepEqual(joined, want) {
+		t.Errorf("joined = %+v, want %+v", joined, want)
+	}
+	if want := []Player{old[3]}; !reflect.DeepEqual(left, want) {
+		t.Errorf("left = %+v, want %+v", left, want)
+	}
+	if want := []Player{new[2], new[3]}; !reflect.DeepEqual(renamed, want) {
+		t.Errorf("renamed = %+v, want %+v", renamed, want)
+	}
+
+	joined, left, renamed = DiffRosters(old, old)
+	if joined != nil || left != nil || renamed != nil {
+		t.Errorf("diff of a roster with itself = %v, %v, %v", joined, left, renamed)
+	}
+	if joined, _, _ := DiffRosters(nil, old); len(joined) != len(old) {
+		t.Errorf("diff from an empty roster joined %d players, want %d", len(joined), len(old))
+	}
+}
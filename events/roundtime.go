@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// AnnotateRoundTime forwards the events from in, setting SinceRoundStart to
+// the time between the latest InitGame's timestamp and the event's. It stays
+// nil for events before the first InitGame, after an InitGame without a
+// timestamp, and for events without a timestamp or one earlier than the
+// InitGame's.
+func AnnotateRoundTime(ctx context.Context, in <-chan Event) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		var start *time.Duration
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if isInitGame(e) {
+					start = e.GetTimestamp()
+				}
+				if b := baseOf(e); b != nil && start != nil && b.Timestamp != nil && *b.Timestamp >= *start {
+					since := *b.Timestamp - *start
+					b.SinceRoundStart = &since
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+
+	return out
+}
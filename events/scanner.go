@@ -0,0 +1,165 @@
+package events
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+const maxScanLineSize = 1024 * 1024
+
+type ScannerOptions struct {
+	Parse ParseOptions
+
+	// StopOnError makes Scan stop at the first line that fails to parse,
+	// with Err returning a *LineError. By default such lines are logged and
+	// skipped.
+	StopOnError bool
+
+	// TrackOffsets records each event's byte range relative to the start of
+	// the reader in BaseEvent.StartOffset and EndOffset.
+	TrackOffsets bool
+
+	// PreprocessLine, if set, is applied to every line before parsing.
+	// Returning false drops the line.
+	PreprocessLine func(string) (string, bool)
+
+	// JoinInitGameContinuations appends lines starting with "\" to the
+	// preceding InitGame line, as TailOptions.JoinInitGameContinuations does.
+	JoinInitGameContinuations bool
+}
+
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+func (e *LineError) Unwrap() error { return e.Err }
+
+// EventScanner reads events from an io.Reader one line at a time, in the
+// manner of bufio.Scanner. Blank lines are skipped.
+type EventScanner struct {
+	opts    ScannerOptions
+	sc      *bufio.Scanner
+	lineNum int
+	event   Event
+	err     error
+
+	offset     int64
+	advance    int
+	timestamps timestampDetector
+
+	// ready holds lines read but not yet parsed, and joiner the InitGame
+	// line held back for continuations.
+	ready  []rawLine
+	joiner initGameJoiner
+}
+
+func NewEventScanner(r io.Reader) *EventScanner {
+	return NewEventScannerWithOptions(r, ScannerOptions{})
+}
+
+func NewEventScannerWithOptions(r io.Reader, opts ScannerOptions) *EventScanner {
+	s := &EventScanner{opts: opts}
+	s.sc = bufio.NewScanner(r)
+	s.sc.Buffer(make([]byte, 0, 64*1024), maxScanLineSize)
+	s.sc.Split(s.scanLines)
+	return s
+}
+
+func (s *EventScanner) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if token != nil {
+		s.advance = advance
+	}
+	return advance, token, err
+}
+
+func (s *EventScanner) Scan() bool {
+	s.event = nil
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		for len(s.ready) > 0 {
+			rl := s.ready[0]
+			s.ready = s.ready[1:]
+			if s.parse(rl) {
+				return true
+			}
+			if s.err != nil {
+				return false
+			}
+		}
+
+		if !s.sc.Scan() {
+			if held, ok := s.joiner.flush(); ok {
+				s.ready = append(s.ready, held)
+				continue
+			}
+			s.err = s.sc.Err()
+			return false
+		}
+
+		s.lineNum++
+		start := s.offset
+		s.offset += int64(s.advance)
+		line := s.sc.Text()
+		if s.opts.PreprocessLine != nil {
+			var ok bool
+			if line, ok = s.opts.PreprocessLine(line); !ok {
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		rl := rawLine{text: line, start: start, end: s.offset, line: s.lineNum}
+		if s.opts.JoinInitGameContinuations {
+			s.ready = append(s.ready, s.joiner.push(rl)...)
+		} else {
+			s.ready = append(s.ready, rl)
+		}
+	}
+}
+
+// parse parses rl into s.event, reporting whether there is an event. On a
+// parse error with StopOnError set it sets s.err instead.
+func (s *EventScanner) parse(rl rawLine) bool {
+	line := rl.text
+	ev, err := ParseEventLineWithOptions(line, s.timestamps.apply(line, s.opts.Parse))
+	if errors.Is(err, ErrCommentLine) {
+		return false
+	}
+	if err != nil {
+		lineErr := &LineError{Line: rl.line, Err: err}
+		if s.opts.StopOnError {
+			s.err = lineErr
+			return false
+		}
+		log.Printf("events: failed to parse event line: %v", lineErr)
+		return false
+	}
+
+	if s.opts.TrackOffsets {
+		if b := baseOf(ev); b != nil {
+			b.StartOffset = rl.start
+			b.EndOffset = rl.end
+		}
+	}
+
+	s.event = ev
+	return true
+}
+
+func (s *EventScanner) Event() Event { return s.event }
+
+func (s *EventScanner) Line() int { return s.lineNum }
+
+func (s *EventScanner) Err() error { return s.err }
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCommentLines(t *testing.T) {
+	opts := ParseOptions{CommentPrefixes: []string{"//", "#", ""}}
+	for _, line := range []string{"// restarted by admin", "  # note", "12:00 // mid-match note"} {
+		if _, err := ParseEventLineWithOptions(line, opts); !errors.Is(err, ErrCommentLine) {
+			t.Errorf("%q: got %v, want ErrCommentLine", line, err)
+		}
+	}
+	if e, err := ParseEventLineWithOptions("J;aa;1;Alice#1", opts); err != nil {
+		t.Errorf("join with '#' in the name: %v", err)
+	} else if j, ok := e.(*JoinEvent); !ok || j.Name != "Alice#1" {
+		t.Errorf("join with '#' in the name parsed as %#v", e)
+	}
+	if _, err := ParseEventLine("// not a comment by default"); errors.Is(err, ErrCommentLine) {
+		t.Error("comment recognized without CommentPrefixes")
+	}
+}
+
+func TestEventScannerSkipsComments(t *testing.T) {
+	logged := captureLog(t)
+
+	input := "# header\nJ;aa;1;Alice\n\n   \n// note\nQ;aa;1;Alice\n"
+	sc := NewEventScannerWithOptions(strings.NewReader(input), ScannerOptions{
+		Parse:       ParseOptions{CommentPrefixes: []string{"#", "//"}},
+		StopOnError: true,
+	})
+	var commands []string
+	for sc.Scan() {
+		commands = append(commands, sc.Event().GetCommand())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(commands, ",") != "J,Q" {
+		t.Errorf("scanned %v, want J,Q", commands)
+	}
+	if logged.Len() != 0 {
+		t.Errorf("comments were logged:\n%s", logged)
+	}
+}
+
+const wrappedInitGame = "0:00 InitGame: \\g_gametype\\dm\n\\mapname\\mp_crash\\sv_hostname\\srv\nJ;aa;1;Alice\n"
+
+func TestEventScannerJoinsInitGameContinuations(t *testing.T) {
+	sc := NewEventScannerWithOptions(strings.NewReader(wrappedInitGame), ScannerOptions{
+		JoinInitGameContinuations: true,
+		TrackOffsets:              true,
+	})
+	var evs []Event
+	for sc.Scan() {
+		evs = append(evs, sc.Event())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2 {
+		t.Fatalf("scanned %d events, want 2", len(evs))
+	}
+	initGame, ok := evs[0].(*ServerEvent)
+	if !ok || initGame.Data["mapname"] != "mp_crash" || initGame.Data["g_gametype"] != "dm" {
+		t.Fatalf("got %#v, want the joined InitGame", evs[0])
+	}
+	if want := int64(strings.Index(wrappedInitGame, "J;")); initGame.EndOffset != want {
+		t.Errorf("InitGame ends at %d, want %d", initGame.EndOffset, want)
+	}
+	if evs[1].GetCommand() != CmdJoin {
+		t.Errorf("second event is %#v, want the join", evs[1])
+	}
+
+	// A held InitGame at the end of the input is still delivered.
+	sc = NewEventScannerWithOptions(strings.NewReader("InitGame: \\g_gametype\\dm\n\\mapname\\mp_crash"), ScannerOptions{
+		JoinInitGameContinuations: true,
+	})
+	if !sc.Scan() || sc.Event().(*ServerEvent).Data["mapname"] != "mp_crash" {
+		t.Fatalf("trailing InitGame not delivered joined: %#v", sc.Event())
+	}
+	if sc.Scan() {
+		t.Errorf("extra event %#v", sc.Event())
+	}
+}
+
+func TestEventScannerKeepsContinuationsByDefault(t *testing.T) {
+	sc := NewEventScanner(strings.NewReader(wrappedInitGame))
+	var evs []Event
+	for sc.Scan() {
+		evs = append(evs, sc.Event())
+	}
+	if len(evs) != 3 {
+		t.Fatalf("scanned %d events, want 3", len(evs))
+	}
+	if _, ok := evs[0].(*ServerEvent).Data["mapname"]; ok {
+		t.Error("continuation joined without JoinInitGameContinuations")
+	}
+	if _, ok := evs[1].(*BaseEvent); !ok {
+		t.Errorf("continuation parsed as %T, want *BaseEvent", evs[1])
+	}
+}
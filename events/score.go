@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+type ScoreEvent struct {
+	BaseEvent
+	Score     int
+	Ping      int
+	ClientNum int
+	Player    string
+}
+
+var scorePattern = regexp.MustCompile(`^score:\s*(-?\d+)\s+ping:\s*(\d+)\s+client:\s*(\d+)\s*(.*)$`)
+
+func parseScoreEvent(line string, ts *time.Duration, raw string) (*ScoreEvent, error) {
+	m := scorePattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("invalid score event line: %q", line)
+	}
+
+	score, _ := strconv.Atoi(m[1])
+	ping, _ := strconv.Atoi(m[2])
+	clientNum, err := strconv.Atoi(m[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", m[3], err)
+	}
+
+	return &ScoreEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   CmdScore,
+			Raw:       raw,
+		},
+		Score:     score,
+		Ping:      ping,
+		ClientNum: clientNum,
+		Player:    m[4],
+	}, nil
+}
+
+// CorrelateFinalScores attaches the standings block an engine writes around
+// ShutdownGame to that event's FinalScores instead of forwarding the score
+// lines individually. Score lines directly before a ShutdownGame and those
+// directly after it (up to the next other event, normally the next InitGame)
+// are attached. To see trailing scores the ShutdownGame is held back until
+// that next event arrives or in is closed.
+//
+// Correlation cannot be determined when any other event separates the score
+// lines from ShutdownGame, e.g. intermission chat; those ScoreEvents are then
+// forwarded unchanged.
+func CorrelateFinalScores(ctx context.Context, in <-chan Event) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		var pending []*ScoreEvent
+		var shutdown *ServerEvent
+
+		send := func(e Event) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- e:
+				return true
+			}
+		}
+		release := func() bool {
+			if shutdown != nil {
+				for _, s := range pending {
+					shutdown.FinalScores = append(shutdown.FinalScores, *s)
+				}
+				pending = nil
+				s := shutdown
+				shutdown = nil
+				return send(s)
+			}
+			for _, s := range pending {
+				if !send(s) {
+					return false
+				}
+			}
+			pending = nil
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					release()
+					return
+				}
+
+				switch t := e.(type) {
+				case *ScoreEvent:
+					// A scoreboard has at most one row per client; more
+					// means these are not a final scoreboard.
+					if len(pending) > maxStrictClientNum && !release() {
+						return
+					}
+					pending = append(pending, t)
+					continue
+				case *ServerEvent:
+					if t.Command == CmdShutdownGame {
+						if shutdown != nil && !release() {
+							return
+						}
+						for _, s := range pending {
+							t.FinalScores = append(t.FinalScores, *s)
+						}
+						pending = nil
+						shutdown = t
+						continue
+					}
+				}
+
+				if !release() || !send(e) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestCorrelateFinalScoresBoundsPending(t *testing.T) {
+	const rows = 300
+	in := make(chan Event, rows+1)
+	for i := 0; i < rows; i++ {
+		e, err := ParseEventLine(fmt.Sprintf("score: %d ping: 50 client: %d P%d", i, i%64, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		in <- e
+	}
+	in <- &ServerEvent{BaseEvent: BaseEvent{Command: CmdShutdownGame}}
+	close(in)
+
+	forwarded, attached := 0, 0
+	for e := range CorrelateFinalScores(context.Background(), in) {
+		switch e := e.(type) {
+		case *ScoreEvent:
+			forwarded++
+		case *ServerEvent:
+			attached = len(e.FinalScores)
+		}
+	}
+	if attached > maxStrictClientNum+1 {
+		t.Errorf("%d scores attached, want at most %d", attached, maxStrictClientNum+1)
+	}
+	if forwarded+attached != rows {
+		t.Errorf("forwarded %d and attached %d scores, want %d in all", forwarded, attached, rows)
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ServerMessageKind string
+
+const (
+	ServerMessagePrint       ServerMessageKind = "print"
+	ServerMessageBroadcast   ServerMessageKind = "broadcast"
+	ServerMessageCenterPrint ServerMessageKind = "centerprint"
+)
+
+// ServerMessageEvent is a message the server displayed to players. Message
+// has its surrounding quotes removed and keeps color codes.
+type ServerMessageEvent struct {
+	BaseEvent
+	Kind    ServerMessageKind
+	Message string
+}
+
+// parseServerMessageEvent parses `print "msg"`, `centerprint "msg"` and
+// `Broadcast: msg` lines. Lines with an empty message are not matched.
+func parseServerMessageEvent(line string, ts *time.Duration, raw string) (*ServerMessageEvent, error) {
+	var kind ServerMessageKind
+	var cmd, rest string
+	switch {
+	case strings.HasPrefix(line, CmdBroadcast+":"):
+		kind, cmd, rest = ServerMessageBroadcast, CmdBroadcast, line[len(CmdBroadcast)+1:]
+	case strings.HasPrefix(line, CmdPrint+" "):
+		kind, cmd, rest = ServerMessagePrint, CmdPrint, line[len(CmdPrint):]
+	case strings.HasPrefix(line, CmdCenterPrint+" "):
+		kind, cmd, rest = ServerMessageCenterPrint, CmdCenterPrint, line[len(CmdCenterPrint):]
+	default:
+		return nil, fmt.Errorf("not a server message event")
+	}
+
+	msg := strings.TrimSpace(rest)
+	if len(msg) >= 2 && msg[0] == '"' && msg[len(msg)-1] == '"' {
+		msg = msg[1 : len(msg)-1]
+	}
+	// Engine prints usually end in a literal "\n".
+	msg = strings.TrimSuffix(msg, `\n`)
+	if strings.TrimSpace(msg) == "" {
+		return nil, fmt.Errorf("empty server message: %q", line)
+	}
+
+	return &ServerMessageEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   cmd,
+			Raw:       raw,
+		},
+		Kind:    kind,
+		Message: msg,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpawnEvent is a player (re)spawning, logged either as "ClientSpawn: <num>"
+// or in the player-event form "Spawn;guid;num;team;name". XUID, Team and
+// Player are only set by the latter.
+type SpawnEvent struct {
+	BaseEvent
+	ClientNum int
+	XUID      string
+	Team      string
+	Player    string
+}
+
+var (
+	spawnMu       sync.RWMutex
+	spawnCommands = map[string]bool{
+		"ClientSpawn": true,
+		"Spawn":       true,
+	}
+)
+
+// RegisterSpawnCommand makes lines starting with cmd, in either spawn line
+// shape, parse as SpawnEvents.
+func RegisterSpawnCommand(cmd string) {
+	spawnMu.Lock()
+	spawnCommands[cmd] = true
+	spawnMu.Unlock()
+}
+
+func isSpawnCommand(cmd string) bool {
+	spawnMu.RLock()
+	ok := spawnCommands[cmd]
+	spawnMu.RUnlock()
+	return ok
+}
+
+func parseSpawnEvent(line string, ts *time.Duration, raw string, base int) (*SpawnEvent, error) {
+	end := strings.IndexAny(line, ":;")
+	if end < 0 || !isSpawnCommand(line[:end]) {
+		return nil, fmt.Errorf("not a spawn event")
+	}
+
+	ev := &SpawnEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   line[:end],
+			Raw:       raw,
+		},
+	}
+
+	if line[end] == ':' {
+		fields := strings.Fields(line[end+1:])
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("invalid spawn event line: %q", line)
+		}
+		clientNum, err := parseClientNum(fields[0], base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client number %q: %w", fields[0], err)
+		}
+		ev.ClientNum = clientNum
+		return ev, nil
+	}
+
+	parts := strings.SplitN(line, ";", 5)
+	if len(parts) < 5 {
+		return nil, fmt.Errorf("invalid spawn event line: %q", line)
+	}
+	clientNum, err := parseClientNum(parts[2], base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", parts[2], err)
+	}
+	ev.XUID = parts[1]
+	ev.ClientNum = clientNum
+	ev.Team = parts[3]
+	ev.Player = parts[4]
+	return ev, nil
+}
+
+// Life is the time a player stayed alive, from a spawn to their next death.
+type Life struct {
+	ClientNum int
+	Spawn     time.Duration
+	Death     time.Duration
+}
+
+func (l Life) Duration() time.Duration { return l.Death - l.Spawn }
+
+// LifeTracker pairs each spawn with the player's next death. A spawn with no
+// death logged since the previous one restarts the life, and a death without
+// a preceding spawn is ignored, as are events without a timestamp.
+// Everything is forgotten on InitGame. It is safe for concurrent use.
+type LifeTracker struct {
+	mu      sync.Mutex
+	spawned map[int]time.Duration
+}
+
+func NewLifeTracker() *LifeTracker {
+	return &LifeTracker{spawned: make(map[int]time.Duration)}
+}
+
+// ApplyEvent records spawns and, for a kill whose victim spawned earlier in
+// the match, returns the victim's life. It returns nil otherwise.
+func (l *LifeTracker) ApplyEvent(e Event) *Life {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch t := e.(type) {
+	case *ServerEvent:
+		if t.Command == CmdInitGame {
+			l.spawned = make(map[int]time.Duration)
+		}
+	case *PlayerEvent:
+		if t.Command == CmdQuit {
+			delete(l.spawned, t.Flag)
+		}
+	case *SpawnEvent:
+		if t.Timestamp != nil && validClientNum(t.ClientNum) {
+			l.spawned[t.ClientNum] = *t.Timestamp
+		}
+	case *KillEvent:
+		spawn, ok := l.spawned[t.VictimClientNum]
+		if !ok || t.Timestamp == nil || *t.Timestamp < spawn {
+			return nil
+		}
+		delete(l.spawned, t.VictimClientNum)
+		return &Life{ClientNum: t.VictimClientNum, Spawn: spawn, Death: *t.Timestamp}
+	}
+	return nil
+}
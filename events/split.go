@@ -0,0 +1,45 @@
+package events
+
+import (
+	"bufio"
+	"io"
+)
+
+// SplitMatches copies the lines of r verbatim into one writer per match,
+// starting a new match at every InitGame line. out is called once per match
+// with its index, counting from 0, before the match's first line; lines
+// before the first InitGame form a preamble written to out(-1). Lines after a
+// ShutdownGame, such as final scores, stay with that match. A nil writer
+// discards the segment. Lines are not parsed, so malformed ones pass through
+// unchanged.
+func SplitMatches(r io.Reader, out func(matchIndex int) io.Writer) error {
+	br := bufio.NewReader(r)
+	index := -1
+	var w io.Writer
+	started := false
+
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if isInitGameLine(line) {
+				index++
+				w = out(index)
+				started = true
+			} else if !started {
+				w = out(-1)
+				started = true
+			}
+			if w != nil {
+				if _, werr := io.WriteString(w, line); werr != nil {
+					return werr
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
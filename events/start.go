@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const startTailerBuffer = 128
+
+// StartTailer runs TailFileWithOptions in a goroutine and returns its event
+// channel, which is closed when the tail ends, and a stop function. Stop
+// cancels the tail, waits for the goroutine to exit and returns the error the
+// tail ended with; ending through stop or ctx cancellation is not an error.
+// Stop may be called more than once, and also after the tail ended on its own.
+//
+// Unless opts.WaitForFile is set, a path that cannot be opened is reported
+// by StartTailer itself and nothing is started.
+func StartTailer(ctx context.Context, path string, opts TailOptions) (<-chan Event, func() error, error) {
+	if !opts.WaitForFile {
+		if err := checkTailPath(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	eventsCh := make(chan Event, startTailerBuffer)
+	done := make(chan struct{})
+	var tailErr error
+
+	go func() {
+		defer close(done)
+		defer close(eventsCh)
+		tailErr = TailFileWithOptions(ctx, path, opts, eventsCh)
+	}()
+
+	var once sync.Once
+	stop := func() error {
+		once.Do(cancel)
+		<-done
+		if errors.Is(tailErr, context.Canceled) {
+			return nil
+		}
+		return tailErr
+	}
+	return eventsCh, stop, nil
+}
+
+func checkTailPath(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if st.IsDir() {
+		return fmt.Errorf("%w: %s", ErrNotAFile, path)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package events
+
+import "sync/atomic"
+
+// TailStats counts what a tailer has done. Pass one in TailOptions.Stats; it
+// may be read from any goroutine while the tail is running, and may be shared
+// by several tailers to get totals.
+type TailStats struct {
+	lines    atomic.Int64
+	events   atomic.Int64
+	unparsed atomic.Int64
+	dropped  atomic.Int64
+}
+
+// Lines returns the number of lines read, including those that were dropped
+// by PreprocessLine or failed to parse.
+func (s *TailStats) Lines() int64 { return s.lines.Load() }
+
+// Events returns the number of events delivered on the events channel.
+func (s *TailStats) Events() int64 { return s.events.Load() }
+
+// Unparsed returns the number of lines that failed to parse.
+func (s *TailStats) Unparsed() int64 { return s.unparsed.Load() }
+
+// Dropped returns the number of events discarded because the events channel
+// was full (see TailOptions.DropWhenFull).
+func (s *TailStats) Dropped() int64 { return s.dropped.Load() }
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// defaultStatusColumns is the layout assumed when the output has no header.
+var defaultStatusColumns = []string{"num", "score", "bot", "ping", "guid", "name", "lastmsg", "address", "qport", "rate"}
+
+// ParseStatus parses the player table of an RCON "status" response. Column
+// positions are taken from the header row, e.g.
+//
+//	num score ping guid name lastmsg address qport rate
+//
+// so layouts with or without the bot, lastmsg, qport and rate columns are
+// understood; without a recognizable header the IW4x layout
+//
+//	num score bot ping guid name lastmsg address qport rate
+//
+// is assumed. Lines that are not player rows, such as the map line, the
+// header and the separator, are skipped. Names may contain spaces.
+func ParseStatus(output string) []Player {
+	columns := defaultStatusColumns
+	var players []Player
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if header, ok := parseStatusHeader(fields); ok {
+			columns = header
+			continue
+		}
+		if p, ok := parseStatusRow(fields, columns); ok {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// parseStatusHeader returns the lowercased column names of a header row,
+// which starts with "num" and has a "name" column.
+func parseStatusHeader(fields []string) ([]string, bool) {
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "num") {
+		return nil, false
+	}
+	columns := make([]string, len(fields))
+	hasName := false
+	for i, f := range fields {
+		columns[i] = strings.ToLower(f)
+		hasName = hasName || columns[i] == "name"
+	}
+	return columns, hasName
+}
+
+// parseStatusRow parses a player row. The columns before the name are
+// matched from the front and those after it from the back, so that the
+// name may contain spaces.
+func parseStatusRow(fields, columns []string) (Player, bool) {
+	nameCol := -1
+	for i, c := range columns {
+		if c == "name" {
+			nameCol = i
+			break
+		}
+	}
+	if nameCol < 0 || len(fields) < len(columns) {
+		return Player{}, false
+	}
+	clientNum, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Player{}, false
+	}
+
+	p := Player{ClientNum: clientNum}
+	after := len(columns) - nameCol - 1
+	p.Name = strings.Join(fields[nameCol:len(fields)-after], " ")
+
+	for i, c := range columns {
+		var value string
+		switch {
+		case i < nameCol:
+			value = fields[i]
+		case i > nameCol:
+			value = fields[len(fields)-(len(columns)-i)]
+		default:
+			continue
+		}
+
+		n, _ := strconv.Atoi(value)
+		switch c {
+		case "guid":
+			p.GUID = value
+		case "address":
+			p.Address = value
+		case "score":
+			p.Score = n
+		case "ping":
+			p.Ping = n
+		case "lastmsg":
+			p.LastMsg = n
+		case "qport":
+			p.QPort = n
+		case "rate":
+			p.Rate = n
+		}
+	}
+	return p, true
+}
+
+// addressHost returns the canonical IP of an address such as "1.2.3.4",
+// "1.2.3.4:28960", "2001:db8::1" or "[2001:db8::1]:28960", without the port.
+// Addresses that do not identify a player, i.e. empty ones, sentinels such as
+// "bot" or "loopback" and loopback IPs, yield "".
+func addressHost(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		return ""
+	}
+	return ip.String()
+}
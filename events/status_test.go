@@ -0,0 +1,55 @@
+package events
+
+import "testing"
+
+func TestAddressHost(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3.4":                "1.2.3.4",
+		" 1.2.3.4:28960 ":        "1.2.3.4",
+		"2001:db8::1":            "2001:db8::1",
+		"[2001:db8::1]":          "2001:db8::1",
+		"[2001:DB8:0::1]:28960":  "2001:db8::1",
+		"::ffff:1.2.3.4":         "1.2.3.4",
+		"[::ffff:1.2.3.4]:28960": "1.2.3.4",
+		"127.0.0.1:28960":        "",
+		"[::1]:28960":            "",
+		"0.0.0.0":                "",
+		"loopback":               "",
+		"bot":                    "",
+		"":                       "",
+		"not an address":         "",
+	}
+	for addr, want := range tests {
+		if got := addressHost(addr); got != want {
+			t.Errorf("addressHost(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestFindByAddressIPv6(t *testing.T) {
+	status := `map: mp_raid
+num score bot ping guid                             name            lastmsg address                       qport rate
+--- ----- --- ---- -------------------------------- --------------- ------- ----------------------------- ----- -----
+  0     0   0   45 0123456789abcdef0123456789abcdef Alice                 0 [2001:db8::1]:28960           1234 25000
+  1     0   0   50 fedcba9876543210fedcba9876543210 Bob Smith             0 1.2.3.4:28961                 4321 25000
+  2     0   1    0 bot0                             Bot                   0 bot                           0    25000
+`
+	players := ParseStatus(status)
+	if len(players) != 3 {
+		t.Fatalf("parsed %d players, want 3", len(players))
+	}
+	if players[0].Address != "[2001:db8::1]:28960" || players[1].Name != "Bob Smith" {
+		t.Fatalf("parsed %+v", players)
+	}
+
+	d := NewPlayerDirectory(staticSource(players), 0)
+	for addr, want := range map[string]int{"2001:db8:0:0::1": 1, "[2001:db8::1]:1": 1, "1.2.3.4": 1, "bot": 0, "[::1]": 0} {
+		found, err := d.FindByAddress(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != want {
+			t.Errorf("FindByAddress(%q) found %d players, want %d", addr, len(found), want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// maxInitGameScan bounds how far back from the end of the file
+// SyntheticInitGame looks for the current match's InitGame line.
+const maxInitGameScan = 16 << 20
+
+// syntheticInitGame returns the last InitGame line before end, parsed and
+// flagged Synthetic, or nil if there is none or it does not name a map.
+func (t *tailer) syntheticInitGame(r io.ReaderAt, end int64) Event {
+	line, start, ok := lastInitGameLine(r, end, t.opts.PreprocessLine)
+	if !ok {
+		return nil
+	}
+	if t.opts.JoinInitGameContinuations {
+		line = joinContinuations(io.NewSectionReader(r, start, end-start), t.opts.PreprocessLine)
+	}
+
+	ev, err := ParseEventLineWithOptions(line, t.opts.Parse)
+	if err != nil {
+		return nil
+	}
+	se, ok := ev.(*ServerEvent)
+	if !ok || se.Data["mapname"] == "" {
+		return nil
+	}
+	se.Synthetic = true
+	if t.opts.Source != "" {
+		se.Source = t.opts.Source
+	}
+	return se
+}
+
+// lastInitGameLine scans r backward from end and returns the last InitGame
+// line and its offset.
+func lastInitGameLine(r io.ReaderAt, end int64, preprocess func(string) (string, bool)) (string, int64, bool) {
+	const chunkSize = 64 << 10
+
+	var data []byte
+	pos := end
+	for pos > 0 && end-pos < maxInitGameScan {
+		n := min(int64(chunkSize), pos)
+		pos -= n
+		chunk := make([]byte, n, n+int64(len(data)))
+		if _, err := r.ReadAt(chunk, pos); err != nil && err != io.EOF {
+			return "", 0, false
+		}
+		data = append(chunk, data...)
+
+		for {
+			data = bytes.TrimSuffix(data, []byte("\n"))
+			i := bytes.LastIndexByte(data, '\n')
+			if i < 0 && pos > 0 {
+				// The line may start in an earlier chunk.
+				break
+			}
+			line := string(data[i+1:])
+			if isInitGameLine(preprocessed(line, preprocess)) {
+				return preprocessed(line, preprocess), pos + int64(i+1), true
+			}
+			if i < 0 {
+				return "", 0, false
+			}
+			data = data[:i]
+		}
+	}
+	return "", 0, false
+}
+
+// joinContinuations reads the InitGame line at the start of r and appends
+// the continuation lines that follow it.
+func joinContinuations(r io.Reader, preprocess func(string) (string, bool)) string {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxInitGameScan)
+
+	var joined string
+	for sc.Scan() {
+		line := strings.TrimSpace(preprocessed(sc.Text(), preprocess))
+		if joined == "" {
+			joined = line
+			continue
+		}
+		if !strings.HasPrefix(line, "\\") {
+			break
+		}
+		joined += line
+	}
+	return joined
+}
+
+func preprocessed(line string, preprocess func(string) (string, bool)) string {
+	line = strings.TrimRight(line, "\r")
+	if preprocess == nil {
+		return line
+	}
+	if out, ok := preprocess(line); ok {
+		return out
+	}
+	return ""
+}
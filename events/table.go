@@ -0,0 +1,59 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+const (
+	tableNameWidth = 24
+	tableGUIDWidth = 8
+)
+
+// FormatTable renders the current roster in the manner of the in-game status
+// output, ordered by client number: client number, the last eight characters
+// of the GUID, and the name without color codes, truncated to fit.
+func (d *PlayerDirectory) FormatTable() (string, error) {
+	players, err := d.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].ClientNum < players[j].ClientNum })
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintln(w, "num\tguid\tname")
+	for _, p := range players {
+		guid := p.GUID
+		if n := len(guid); n > tableGUIDWidth {
+			guid = guid[n-tableGUIDWidth:]
+		}
+		name := truncateRunes(p.CleanName(), tableNameWidth)
+		fmt.Fprintf(w, "%d\t%s\t%s\n", p.ClientNum, guid, name)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// truncateRunes shortens s to at most n runes, ending it with "…" when cut,
+// without splitting a multi-byte character.
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i, count := 0, 0
+	for i < len(s) && count < n-1 {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		count++
+	}
+	return s[:i] + "…"
+}
@@ -2,54 +2,214 @@ package events
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
+type TailOptions struct {
+	StartAtEnd bool
+
+	// PinSymlinkTarget resolves path once at start and tails the resolved
+	// file. By default a symlinked path is re-resolved on every rotation
+	// check, so repointing the link switches to the new target.
+	PinSymlinkTarget bool
+
+	// Source is copied onto every emitted event's BaseEvent.Source, so that
+	// several tailers can share one channel and still be told apart.
+	Source string
+
+	Parse ParseOptions
+
+	// WaitForFile keeps retrying while path does not exist yet instead of
+	// failing, until it appears or the context is cancelled. Other open
+	// errors still fail immediately.
+	WaitForFile bool
+
+	// DrainTimeout, when positive, makes the tailer finish sending the
+	// complete lines it has already read into its buffer after the context is
+	// cancelled, before returning ctx.Err(). Draining stops once the timeout
+	// elapses, so a consumer that stopped reading cannot block shutdown. Lines
+	// not yet read from the file are not drained.
+	DrainTimeout time.Duration
+
+	// JoinInitGameContinuations appends lines starting with "\" to the
+	// preceding InitGame line, for engines that wrap long cvar dumps. The
+	// InitGame event is held back until the next line arrives or the end of
+	// the file is reached.
+	JoinInitGameContinuations bool
+
+	// ErrorLogInterval limits parse-error logging to one message per
+	// interval; each message reports how many were suppressed before it.
+	// Zero uses a default of 5 seconds and a negative value logs every
+	// error.
+	ErrorLogInterval time.Duration
+
+	// TrackOffsets records each event's byte range in the file in
+	// BaseEvent.StartOffset and EndOffset. EndOffset includes the line
+	// terminator. Offsets restart at zero when the file is rotated.
+	TrackOffsets bool
+
+	// PreprocessLine, if set, is applied to every line (without its line
+	// terminator) before parsing. Returning false drops the line.
+	PreprocessLine func(string) (string, bool)
+
+	// OnUnparsed, if set, is called from the tailing goroutine with the raw
+	// text of lines that fail to parse, at most once per ErrorLogInterval
+	// like the log messages. It must not block.
+	OnUnparsed func(raw string, err error)
+
+	// AdaptivePoll replaces the fixed 150ms poll at end of file with one that
+	// starts at MinPollInterval, doubles after every poll that finds nothing
+	// new up to MaxPollInterval, and drops back to the minimum once a line is
+	// read. Zero bounds default to 25ms and 2s.
+	AdaptivePoll    bool
+	MinPollInterval time.Duration
+	MaxPollInterval time.Duration
+
+	// Tee, if set, receives every line verbatim, including its terminator,
+	// before preprocessing and parsing, so it also gets lines that fail to
+	// parse or are dropped. Write errors are logged and do not stop the tail.
+	// Flushing and closing the writer is up to the caller.
+	Tee io.Writer
+
+	// SyntheticInitGame, with StartAtEnd, makes the tailer look back through
+	// the file for the current match's InitGame line and emit it first,
+	// flagged ServerEvent.Synthetic, so that stateful consumers learn the map
+	// when tailing starts mid-match. Nothing is emitted if no InitGame naming
+	// a map is found within the last 16MB.
+	SyntheticInitGame bool
+
+	// DropWhenFull makes the tailer discard an event instead of waiting when
+	// eventsCh is full, so that a slow consumer cannot stall reading and
+	// rotation detection. The price is lost events: each drop is counted in
+	// Stats and reported to OnDrop. By default the tailer blocks until the
+	// consumer receives, losing nothing but falling behind the file. Give
+	// eventsCh a buffer when using this, or every event arriving while the
+	// consumer is busy is dropped.
+	DropWhenFull bool
+
+	// OnDrop, if set, is called from the tailing goroutine with every event
+	// discarded by DropWhenFull. It must not block.
+	OnDrop func(Event)
+
+	// Stats, if set, is updated with the tailer's counters as it runs.
+	Stats *TailStats
+
+	// Monitor, if set, tracks the tailer's liveness for health checks.
+	Monitor *TailMonitor
+}
+
+const (
+	defaultErrorLogInterval = 5 * time.Second
+	defaultPollInterval     = 150 * time.Millisecond
+	defaultMinPollInterval  = 25 * time.Millisecond
+	defaultMaxPollInterval  = 2 * time.Second
+)
+
+var ErrNotAFile = errors.New("path is not a file")
+
 func TailFileContext(ctx context.Context, path string, startAtEnd bool, eventsCh chan<- Event) error {
-	const pollInterval = 150 * time.Millisecond
+	return TailFileWithOptions(ctx, path, TailOptions{StartAtEnd: startAtEnd}, eventsCh)
+}
+
+func TailFileWithOptions(ctx context.Context, path string, opts TailOptions, eventsCh chan<- Event) error {
+	return tailFile(ctx, path, opts, eventsCh, realClock{})
+}
+
+func tailFile(ctx context.Context, path string, opts TailOptions, eventsCh chan<- Event, clk clock) error {
+	return newTailer(opts, eventsCh, clk).run(ctx, path)
+}
+
+// run tails path until ctx is cancelled or an error occurs.
+func (t *tailer) run(ctx context.Context, path string) error {
 	const reopenRetry = 200 * time.Millisecond
 
+	opts := t.opts
+	defer t.done()
+
+	if opts.WaitForFile {
+		if err := waitForFile(ctx, t.clock, path, reopenRetry); err != nil {
+			return err
+		}
+	}
+
+	if opts.PinSymlinkTarget {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return err
+		}
+		path = resolved
+	}
+
 	openFile := func() (*os.File, error) {
-		return os.Open(path)
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		if st, err := f.Stat(); err == nil && st.IsDir() {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s", ErrNotAFile, path)
+		}
+		return f, nil
 	}
 
 	f, err := openFile()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer func() { f.Close() }()
 
-	if startAtEnd {
-		if _, err := f.Seek(0, io.SeekEnd); err != nil {
-			return err
-		}
-	} else {
-		if _, err := f.Seek(0, io.SeekStart); err != nil {
-			return err
-		}
+	whence := io.SeekStart
+	if opts.StartAtEnd {
+		whence = io.SeekEnd
+	}
+	offset, err := f.Seek(0, whence)
+	if err != nil {
+		return err
 	}
 
 	buf := bufio.NewReader(f)
+	t.offset = offset
+
+	if opts.StartAtEnd && opts.SyntheticInitGame {
+		if ev := t.syntheticInitGame(f, offset); ev != nil {
+			if err := t.send(ctx, ev); err != nil {
+				return t.stop(ctx, nil)
+			}
+		}
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return t.stop(ctx, buf)
 		default:
 		}
 
 		line, err := buf.ReadString('\n')
+		if line != "" {
+			t.poll.reset()
+		}
 		if err != nil {
 			if err == io.EOF {
+				// Keep an incomplete last line until the rest is written.
+				t.partial += line
+
 				stat, statErr := os.Stat(path)
 				if statErr == nil {
 					curStat, _ := f.Stat()
 					if !os.SameFile(stat, curStat) || stat.Size() < currentOffset(f) {
+						if err := t.flushPartial(ctx); err != nil {
+							return t.stop(ctx, buf)
+						}
 						f.Close()
 						var nf *os.File
 						for {
@@ -62,40 +222,358 @@ func TailFileContext(ctx context.Context, path string, startAtEnd bool, eventsCh
 							if err == nil {
 								break
 							}
-							time.Sleep(reopenRetry)
+							if opts.Monitor != nil {
+								opts.Monitor.failed()
+							}
+							if errors.Is(err, ErrNotAFile) {
+								return err
+							}
+							select {
+							case <-ctx.Done():
+								return ctx.Err()
+							case <-t.clock.After(reopenRetry):
+							}
 						}
 						f = nf
 						buf = bufio.NewReader(f)
+						t.offset = 0
+						t.timestamps = timestampDetector{}
+						if opts.Monitor != nil {
+							opts.Monitor.reopens.Add(1)
+						}
 						continue
 					}
 				}
 
+				if err := t.flushHeld(ctx); err != nil {
+					return t.stop(ctx, buf)
+				}
+
 				select {
 				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(pollInterval):
+					return t.stop(ctx, buf)
+				case <-t.clock.After(t.poll.next()):
 				}
 				continue
 			}
 			return err
 		}
 
-		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
-			continue
+		if t.partial != "" {
+			line = t.partial + line
+			t.partial = ""
 		}
+		if err := t.handleRawLine(ctx, line); err != nil {
+			return t.stop(ctx, buf)
+		}
+	}
+}
 
-		ev, err := ParseEventLine(line)
-		if err != nil {
-			log.Printf("events: failed to parse event line: %v", err)
-			continue
+type tailer struct {
+	opts     TailOptions
+	eventsCh chan<- Event
+	clock    clock
+
+	// pending holds an event whose send was interrupted by cancellation, so
+	// that draining can deliver it first.
+	pending Event
+
+	joiner     initGameJoiner
+	timestamps timestampDetector
+
+	// pool, if set, parses lines on other goroutines; see TailFileConcurrent.
+	pool *parsePool
+
+	errLog      rateLimiter
+	unparsedLim rateLimiter
+	poll        pollInterval
+
+	// offset is the file position just past the last consumed line, and
+	// partial an unterminated line read at end of file.
+	offset  int64
+	partial string
+}
+
+type rawLine struct {
+	text       string
+	start, end int64
+
+	// line is the line number, counted from 1; only EventScanner sets it.
+	line int
+}
+
+func newTailer(opts TailOptions, eventsCh chan<- Event, clk clock) *tailer {
+	interval := opts.ErrorLogInterval
+	if interval == 0 {
+		interval = defaultErrorLogInterval
+	}
+	if opts.Monitor != nil {
+		opts.Monitor.start(eventsCh)
+	}
+	return &tailer{
+		opts:        opts,
+		eventsCh:    eventsCh,
+		clock:       clk,
+		errLog:      rateLimiter{interval: interval},
+		unparsedLim: rateLimiter{interval: interval},
+		poll:        newPollInterval(opts),
+	}
+}
+
+// done marks the tailer as stopped on its monitor.
+func (t *tailer) done() {
+	if t.opts.Monitor != nil {
+		t.opts.Monitor.alive.Store(false)
+	}
+}
+
+// handleRawLine consumes one line as read from the source, including its
+// terminator.
+func (t *tailer) handleRawLine(ctx context.Context, text string) error {
+	rl := rawLine{text: text, start: t.offset, end: t.offset + int64(len(text))}
+	t.offset = rl.end
+	if t.opts.Stats != nil {
+		t.opts.Stats.lines.Add(1)
+	}
+	if t.opts.Monitor != nil {
+		t.opts.Monitor.read(t.clock.Now())
+	}
+
+	if t.opts.Tee != nil {
+		if _, err := io.WriteString(t.opts.Tee, text); err != nil {
+			if ok, _ := t.errLog.allow(t.clock.Now()); ok {
+				log.Printf("events: failed to write line to tee: %v", err)
+			}
+		}
+	}
+
+	if t.opts.PreprocessLine != nil {
+		text, ok := t.opts.PreprocessLine(strings.TrimRight(rl.text, "\r\n"))
+		if !ok {
+			return nil
 		}
+		rl.text = text
+	}
+
+	if !t.opts.JoinInitGameContinuations {
+		return t.handleLine(ctx, rl)
+	}
+
+	for _, ready := range t.joiner.push(rl) {
+		if err := t.handleLine(ctx, ready); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushHeld handles the InitGame line held for continuations and waits for
+// the lines still being parsed by the pool.
+func (t *tailer) flushHeld(ctx context.Context) error {
+	if held, ok := t.joiner.flush(); ok {
+		if err := t.handleLine(ctx, held); err != nil {
+			return err
+		}
+	}
+	if t.pool != nil {
+		return t.pool.flush(ctx, t)
+	}
+	return nil
+}
+
+func (t *tailer) flushPartial(ctx context.Context) error {
+	if t.partial == "" {
+		return nil
+	}
+	partial := t.partial
+	t.partial = ""
+	if err := t.handleRawLine(ctx, partial); err != nil {
+		return err
+	}
+	return t.flushHeld(ctx)
+}
+
+func (t *tailer) handleLine(ctx context.Context, rl rawLine) error {
+	line := strings.TrimRight(rl.text, "\r\n")
+	if line == "" {
+		return nil
+	}
+
+	opts := t.timestamps.apply(line, t.opts.Parse)
+	if t.pool != nil {
+		return t.pool.submit(ctx, t, parseJob{line: line, opts: opts, rl: rl})
+	}
+	ev, err := ParseEventLineWithOptions(line, opts)
+	return t.handleParsed(ctx, rl, line, ev, err)
+}
 
+// handleParsed delivers the result of parsing line.
+func (t *tailer) handleParsed(ctx context.Context, rl rawLine, line string, ev Event, err error) error {
+	if errors.Is(err, ErrCommentLine) {
+		return nil
+	}
+	if err != nil {
+		if t.opts.Stats != nil {
+			t.opts.Stats.unparsed.Add(1)
+		}
+		if t.opts.Monitor != nil {
+			t.opts.Monitor.failed()
+		}
+		t.logParseError(err)
+		t.reportUnparsed(line, err)
+		return nil
+	}
+	if t.opts.Monitor != nil {
+		t.opts.Monitor.parsed()
+	}
+	if b := baseOf(ev); b != nil {
+		if t.opts.Source != "" {
+			b.Source = t.opts.Source
+		}
+		if t.opts.TrackOffsets {
+			b.StartOffset = rl.start
+			b.EndOffset = rl.end
+		}
+	}
+
+	return t.send(ctx, ev)
+}
+
+func (t *tailer) logParseError(err error) {
+	ok, suppressed := t.errLog.allow(t.clock.Now())
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		log.Printf("events: failed to parse event line: %v (%d similar errors suppressed)", err, suppressed)
+		return
+	}
+	log.Printf("events: failed to parse event line: %v", err)
+}
+
+func (t *tailer) reportUnparsed(raw string, err error) {
+	if t.opts.OnUnparsed == nil {
+		return
+	}
+	if ok, _ := t.unparsedLim.allow(t.clock.Now()); ok {
+		t.opts.OnUnparsed(raw, err)
+	}
+}
+
+func (t *tailer) send(ctx context.Context, ev Event) error {
+	if t.opts.DropWhenFull {
+		if err := ctx.Err(); err != nil {
+			t.pending = ev
+			return err
+		}
 		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case eventsCh <- ev:
+		case t.eventsCh <- ev:
+			t.sent()
+		default:
+			if t.opts.Stats != nil {
+				t.opts.Stats.dropped.Add(1)
+			}
+			if t.opts.OnDrop != nil {
+				t.opts.OnDrop(ev)
+			}
+		}
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		t.pending = ev
+		return ctx.Err()
+	case t.eventsCh <- ev:
+		t.sent()
+		return nil
+	}
+}
+
+func (t *tailer) sent() {
+	if t.opts.Stats != nil {
+		t.opts.Stats.events.Add(1)
+	}
+}
+
+// stop drains buffered lines if DrainTimeout is set and returns ctx.Err().
+func (t *tailer) stop(ctx context.Context, buf *bufio.Reader) error {
+	if t.opts.DrainTimeout > 0 {
+		dctx, cancel := context.WithTimeout(context.Background(), t.opts.DrainTimeout)
+		t.drain(dctx, buf)
+		cancel()
+	}
+	return ctx.Err()
+}
+
+func (t *tailer) drain(ctx context.Context, buf *bufio.Reader) {
+	if t.pending != nil {
+		ev := t.pending
+		t.pending = nil
+		if t.send(ctx, ev) != nil {
+			return
+		}
+	}
+
+	if buf == nil {
+		t.flushHeld(ctx)
+		return
+	}
+	data, _ := buf.Peek(buf.Buffered())
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			t.flushHeld(ctx)
+			return
 		}
+		text := string(data[:i+1])
+		if t.partial != "" {
+			text = t.partial + text
+			t.partial = ""
+		}
+		if t.handleRawLine(ctx, text) != nil {
+			return
+		}
+		data = data[i+1:]
+	}
+}
+
+// pollInterval yields the wait before each poll at end of file.
+type pollInterval struct {
+	adaptive bool
+	min, max time.Duration
+	cur      time.Duration
+}
+
+func newPollInterval(opts TailOptions) pollInterval {
+	if !opts.AdaptivePoll {
+		return pollInterval{cur: defaultPollInterval}
+	}
+	p := pollInterval{adaptive: true, min: opts.MinPollInterval, max: opts.MaxPollInterval}
+	if p.min <= 0 {
+		p.min = defaultMinPollInterval
+	}
+	if p.max <= 0 {
+		p.max = defaultMaxPollInterval
+	}
+	if p.max < p.min {
+		p.max = p.min
+	}
+	p.cur = p.min
+	return p
+}
+
+func (p *pollInterval) next() time.Duration {
+	d := p.cur
+	if p.adaptive {
+		p.cur = min(p.cur*2, p.max)
+	}
+	return d
+}
+
+func (p *pollInterval) reset() {
+	if p.adaptive {
+		p.cur = p.min
 	}
 }
 
@@ -103,6 +581,21 @@ func TailFile(path string, startAtEnd bool, eventsCh chan<- Event) error {
 	return TailFileContext(context.Background(), path, startAtEnd, eventsCh)
 }
 
+func waitForFile(ctx context.Context, clk clock, path string, retry time.Duration) error {
+	for {
+		_, err := os.Stat(path)
+		if err == nil || !os.IsNotExist(err) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clk.After(retry):
+		}
+	}
+}
+
 func currentOffset(f *os.File) int64 {
 	off, err := f.Seek(0, io.SeekCurrent)
 	if err != nil {
@@ -0,0 +1,247 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return nil
+	}
+}
+
+func TestTailAdaptivePoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games_mp.log")
+	writeFile(t, path, "J;aa;1;Alice\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := newFakeClock()
+	eventsCh := make(chan Event, 8)
+	done := make(chan error, 1)
+	opts := TailOptions{AdaptivePoll: true, MinPollInterval: 10 * time.Millisecond, MaxPollInterval: 40 * time.Millisecond}
+	go func() { done <- tailFile(ctx, path, opts, eventsCh, clk) }()
+
+	receive(t, eventsCh)
+
+	// Idle polls back off up to the maximum.
+	for _, want := range []time.Duration{10, 20, 40, 40} {
+		clk.waitForTimers(t, 1)
+		if got := clk.lastAfter(); got != want*time.Millisecond {
+			t.Fatalf("idle poll interval = %v, want %v", got, want*time.Millisecond)
+		}
+		clk.Advance(clk.lastAfter())
+	}
+
+	// A line brings the interval back to the minimum.
+	clk.waitForTimers(t, 1)
+	appendFile(t, path, "Q;aa;1;Alice\n")
+	clk.Advance(clk.lastAfter())
+	receive(t, eventsCh)
+	clk.waitForTimers(t, 1)
+	if got := clk.lastAfter(); got != 10*time.Millisecond {
+		t.Fatalf("poll interval after a read = %v, want 10ms", got)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("tail returned %v, want context.Canceled", err)
+	}
+}
+
+func TestTailFixedPollInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games_mp.log")
+	writeFile(t, path, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := newFakeClock()
+	done := make(chan error, 1)
+	go func() { done <- tailFile(ctx, path, TailOptions{}, make(chan Event), clk) }()
+
+	for i := 0; i < 3; i++ {
+		clk.waitForTimers(t, 1)
+		if got := clk.lastAfter(); got != defaultPollInterval {
+			t.Fatalf("poll interval = %v, want %v", got, defaultPollInterval)
+		}
+		clk.Advance(defaultPollInterval)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestTailFollowsRepointedSymlink(t *testing.T) {
+	for _, pin := range []bool{false, true} {
+		dir := t.TempDir()
+		a, b, link := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log"), filepath.Join(dir, "games_mp.log")
+		writeFile(t, a, "J;aa;1;Alice\n")
+		writeFile(t, b, "J;bb;2;Bob\n")
+		if err := os.Symlink(a, link); err != nil {
+			t.Skip("symlinks not supported:", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		eventsCh := make(chan Event)
+		done := make(chan error, 1)
+		opts := TailOptions{PinSymlinkTarget: pin, AdaptivePoll: true, MinPollInterval: time.Millisecond, MaxPollInterval: 5 * time.Millisecond}
+		go func() { done <- TailFileWithOptions(ctx, link, opts, eventsCh) }()
+
+		if j := receive(t, eventsCh).(*JoinEvent); j.Name != "Alice" {
+			t.Fatalf("pin=%v: first event by %q, want Alice", pin, j.Name)
+		}
+
+		if err := os.Remove(link); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(b, link); err != nil {
+			t.Fatal(err)
+		}
+		// Unpinned, the line appended to the old target is read before the
+		// link is checked again, so only the pinned tail gets one.
+		want := "Bob"
+		if pin {
+			appendFile(t, a, "Q;aa;1;Alice\n")
+			want = "Alice"
+		}
+		switch e := receive(t, eventsCh).(type) {
+		case *JoinEvent:
+			if e.Name != want {
+				t.Errorf("pin=%v: got a join by %q, want %q", pin, e.Name, want)
+			}
+		case *PlayerEvent:
+			if e.Player != want {
+				t.Errorf("pin=%v: got %s by %q, want %q", pin, e.Command, e.Player, want)
+			}
+		default:
+			t.Errorf("pin=%v: got %T", pin, e)
+		}
+
+		cancel()
+		<-done
+	}
+}
+
+func TestTailDirectoryIsNotAFile(t *testing.T) {
+	err := TailFileWithOptions(context.Background(), t.TempDir(), TailOptions{}, make(chan Event))
+	if !errors.Is(err, ErrNotAFile) {
+		t.Fatalf("got %v, want ErrNotAFile", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = TailFileWithOptions(ctx, t.TempDir(), TailOptions{WaitForFile: true}, make(chan Event))
+	if !errors.Is(err, ErrNotAFile) {
+		t.Fatalf("WaitForFile: got %v, want ErrNotAFile", err)
+	}
+}
+
+func TestTailRotatedToDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games_mp.log")
+	writeFile(t, path, "J;aa;1;Alice\n")
+
+	eventsCh := make(chan Event)
+	done := make(chan error, 1)
+	opts := TailOptions{AdaptivePoll: true, MinPollInterval: time.Millisecond, MaxPollInterval: 5 * time.Millisecond}
+	go func() { done <- TailFileWithOptions(context.Background(), path, opts, eventsCh) }()
+	receive(t, eventsCh)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrNotAFile) {
+			t.Fatalf("got %v, want ErrNotAFile", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tail did not stop")
+	}
+}
+
+func TestPollIntervalBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TailOptions
+		want []time.Duration
+	}{
+		{"fixed", TailOptions{}, []time.Duration{150, 150, 150}},
+		{"defaults", TailOptions{AdaptivePoll: true}, []time.Duration{25, 50, 100, 200, 400, 800, 1600, 2000, 2000}},
+		{"max below min", TailOptions{AdaptivePoll: true, MinPollInterval: 30 * time.Millisecond, MaxPollInterval: 10 * time.Millisecond}, []time.Duration{30, 30}},
+	}
+	for _, tt := range tests {
+		p := newPollInterval(tt.opts)
+		for i, want := range tt.want {
+			if got := p.next(); got != want*time.Millisecond {
+				t.Errorf("%s: poll %d waited %v, want %v", tt.name, i, got, want*time.Millisecond)
+			}
+		}
+		p.reset()
+		if got := p.next(); got != tt.want[0]*time.Millisecond {
+			t.Errorf("%s: after reset waited %v, want %v", tt.name, got, tt.want[0]*time.Millisecond)
+		}
+	}
+}
+
+func TestTailJoinsInitGameContinuations(t *testing.T) {
+	for _, join := range []bool{true, false} {
+		path := filepath.Join(t.TempDir(), "games_mp.log")
+		writeFile(t, path, wrappedInitGame)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		eventsCh := make(chan Event, 8)
+		done := make(chan error, 1)
+		opts := TailOptions{JoinInitGameContinuations: join}
+		go func() { done <- TailFileWithOptions(ctx, path, opts, eventsCh) }()
+
+		initGame := receive(t, eventsCh).(*ServerEvent)
+		if _, joined := initGame.Data["mapname"]; joined != join {
+			t.Errorf("JoinInitGameContinuations %v: mapname in InitGame = %v", join, joined)
+		}
+		if !join {
+			if _, ok := receive(t, eventsCh).(*BaseEvent); !ok {
+				t.Error("continuation not delivered on its own")
+			}
+		}
+		if e := receive(t, eventsCh); e.GetCommand() != CmdJoin {
+			t.Errorf("JoinInitGameContinuations %v: got %#v, want the join", join, e)
+		}
+
+		cancel()
+		<-done
+	}
+}
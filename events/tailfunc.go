@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// TailFunc is like TailFuncWithOptions with default options.
+func TailFunc(ctx context.Context, next func() (string, error), eventsCh chan<- Event) error {
+	return TailFuncWithOptions(ctx, next, TailOptions{}, eventsCh)
+}
+
+// TailFuncWithOptions parses the lines returned by next and sends the events
+// on eventsCh, for line sources other than files. next blocks until a line is
+// available; a line returned together with an error is still processed.
+// io.EOF from next ends the tail with a nil error, and any other error is
+// returned. The context is checked between calls, so next should return
+// promptly once it is cancelled.
+//
+// Options that concern files (StartAtEnd, PinSymlinkTarget, WaitForFile) are
+// ignored, and offsets count the bytes of the returned lines.
+func TailFuncWithOptions(ctx context.Context, next func() (string, error), opts TailOptions, eventsCh chan<- Event) error {
+	t := newTailer(opts, eventsCh, realClock{})
+	defer t.done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return t.stop(ctx, nil)
+		default:
+		}
+
+		line, err := next()
+		if line != "" {
+			if err := t.handleRawLine(ctx, line); err != nil {
+				return t.stop(ctx, nil)
+			}
+		}
+		if err != nil {
+			if err := t.flushHeld(ctx); err != nil {
+				return t.stop(ctx, nil)
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package events
+
+import "strings"
+
+type Team int
+
+const (
+	TeamUnknown Team = iota
+	TeamAllies
+	TeamAxis
+	TeamSpectator
+	TeamWorld
+
+	// TeamNone is the empty team of free-for-all game types, where every
+	// other player is an enemy.
+	TeamNone
+)
+
+var teamTokens = map[Team]string{
+	TeamUnknown:   "unknown",
+	TeamAllies:    "allies",
+	TeamAxis:      "axis",
+	TeamSpectator: "spectator",
+	TeamWorld:     "world",
+	TeamNone:      "none",
+}
+
+var teamAliases = map[string]Team{
+	"allies":     TeamAllies,
+	"ally":       TeamAllies,
+	"blue":       TeamAllies,
+	"axis":       TeamAxis,
+	"red":        TeamAxis,
+	"spectator":  TeamSpectator,
+	"spectators": TeamSpectator,
+	"spec":       TeamSpectator,
+	"world":      TeamWorld,
+	"":           TeamNone,
+	"none":       TeamNone,
+}
+
+// String returns the engine's lowercase team token, suitable for RCON
+// commands.
+func (t Team) String() string {
+	if s, ok := teamTokens[t]; ok {
+		return s
+	}
+	return teamTokens[TeamUnknown]
+}
+
+// ParseTeam accepts engine tokens and common labels such as "Axis" or "red",
+// case-insensitively. An empty team is TeamNone; anything else is
+// TeamUnknown. ParseTeam(t.String()) returns t for every defined Team.
+func ParseTeam(s string) Team {
+	if t, ok := teamAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return t
+	}
+	return TeamUnknown
+}
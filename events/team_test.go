@@ -0,0 +1,33 @@
+package events
+
+import "testing"
+
+func TestTeamRoundTrip(t *testing.T) {
+	for team := range teamTokens {
+		if got := ParseTeam(team.String()); got != team {
+			t.Errorf("ParseTeam(%q) = %v, want %v", team.String(), got, team)
+		}
+	}
+	if got := Team(99).String(); got != "unknown" {
+		t.Errorf("Team(99).String() = %q, want unknown", got)
+	}
+}
+
+func TestParseTeam(t *testing.T) {
+	tests := map[string]Team{
+		"allies":     TeamAllies,
+		" Axis ":     TeamAxis,
+		"RED":        TeamAxis,
+		"blue":       TeamAllies,
+		"Spectators": TeamSpectator,
+		"world":      TeamWorld,
+		"":           TeamNone,
+		"none":       TeamNone,
+		"marines":    TeamUnknown,
+	}
+	for s, want := range tests {
+		if got := ParseTeam(s); got != want {
+			t.Errorf("ParseTeam(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
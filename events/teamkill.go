@@ -0,0 +1,168 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultTeamKillWindow       = 5 * time.Minute
+	defaultTeamKillThreshold    = 3
+	defaultTeamKillMaxAttackers = 256
+)
+
+type TeamKillOptions struct {
+	// Window is the sliding window, by log timestamp, in which team kills
+	// are counted. Kills that lack a timestamp, or are compared with one
+	// that does, are windowed by the time they were applied instead. Zero
+	// uses a default of 5 minutes.
+	Window time.Duration
+
+	// Threshold is the number of team kills within the window at which
+	// OnThreshold fires. Zero uses a default of 3.
+	Threshold int
+
+	// MaxAttackers bounds the number of players tracked at once; beyond it
+	// the player whose last team kill is oldest is dropped. Zero uses a
+	// default of 256.
+	MaxAttackers int
+
+	// OnThreshold is called, outside the tracker's lock, for every team kill
+	// that brings the attacker's count to Threshold or beyond, so repeated
+	// offences can be escalated from a warning to a kick.
+	OnThreshold func(TeamKillAlert)
+}
+
+// TeamKillAlert identifies a player who reached the team-kill threshold.
+type TeamKillAlert struct {
+	GUID      string
+	ClientNum int
+	Name      string
+
+	// Count is the number of team kills within the window, including Kill.
+	Count int
+	Kill  *KillEvent
+}
+
+// TeamKillTracker counts each player's friendly-fire kills, as reported by
+// KillEvent.IsFriendlyFire, within a sliding window. Counts are reset on
+// InitGame. It is safe for concurrent use.
+type TeamKillTracker struct {
+	opts  TeamKillOptions
+	clock clock
+
+	mu        sync.Mutex
+	attackers map[string]*teamKills
+	seq       uint64
+}
+
+type teamKills struct {
+	kills []teamKill
+	last  uint64
+}
+
+type teamKill struct {
+	ts      *time.Duration
+	applied time.Time
+}
+
+func NewTeamKillTracker(opts TeamKillOptions) *TeamKillTracker {
+	if opts.Window <= 0 {
+		opts.Window = defaultTeamKillWindow
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaultTeamKillThreshold
+	}
+	if opts.MaxAttackers <= 0 {
+		opts.MaxAttackers = defaultTeamKillMaxAttackers
+	}
+	return &TeamKillTracker{opts: opts, clock: realClock{}, attackers: make(map[string]*teamKills)}
+}
+
+func (t *TeamKillTracker) ApplyEvent(e Event) {
+	switch k := e.(type) {
+	case *ServerEvent:
+		if k.Command == CmdInitGame {
+			t.Reset()
+		}
+	case *KillEvent:
+		if !k.IsFriendlyFire() {
+			return
+		}
+		count := t.record(k)
+		if count >= t.opts.Threshold && t.opts.OnThreshold != nil {
+			t.opts.OnThreshold(TeamKillAlert{
+				GUID:      k.AttackerXUID,
+				ClientNum: k.AttackerClientNum,
+				Name:      k.AttackerName,
+				Count:     count,
+				Kill:      k,
+			})
+		}
+	}
+}
+
+// Count returns the number of team kills recorded for the player within the
+// window ending at their latest one.
+func (t *TeamKillTracker) Count(guid, name string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tk, ok := t.attackers[scoreKey(guid, name)]; ok {
+		return len(tk.kills)
+	}
+	return 0
+}
+
+func (t *TeamKillTracker) Reset() {
+	t.mu.Lock()
+	t.attackers = make(map[string]*teamKills)
+	t.mu.Unlock()
+}
+
+// record adds the kill and returns the attacker's count within the window.
+func (t *TeamKillTracker) record(k *KillEvent) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := scoreKey(k.AttackerXUID, k.AttackerName)
+	tk, ok := t.attackers[key]
+	if !ok {
+		if len(t.attackers) >= t.opts.MaxAttackers {
+			t.evictLocked()
+		}
+		tk = &teamKills{}
+		t.attackers[key] = tk
+	}
+	t.seq++
+	tk.last = t.seq
+
+	now := t.clock.Now()
+	kept := tk.kills[:0]
+	for _, prev := range tk.kills {
+		if t.inWindow(prev, k.Timestamp, now) {
+			kept = append(kept, prev)
+		}
+	}
+	tk.kills = append(kept, teamKill{ts: k.Timestamp, applied: now})
+	return len(tk.kills)
+}
+
+// inWindow reports whether prev is still within the window of a kill at ts
+// applied at now.
+func (t *TeamKillTracker) inWindow(prev teamKill, ts *time.Duration, now time.Time) bool {
+	if prev.ts != nil && ts != nil {
+		return *prev.ts <= *ts && *ts-*prev.ts < t.opts.Window
+	}
+	return now.Sub(prev.applied) < t.opts.Window
+}
+
+func (t *TeamKillTracker) evictLocked() {
+	var oldest string
+	var oldestSeq uint64
+	for key, tk := range t.attackers {
+		if oldest == "" || tk.last < oldestSeq {
+			oldest, oldestSeq = key, tk.last
+		}
+	}
+	delete(t.attackers, oldest)
+}
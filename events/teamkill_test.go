@@ -0,0 +1,83 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func friendlyKill(t *testing.T, ts string) *KillEvent {
+	t.Helper()
+	line := "K;aa;0;axis;Alice;bb;1;axis;Bob;ak47_mp;100;MOD_RIFLE_BULLET;head"
+	if ts != "" {
+		line = ts + " " + line
+	}
+	e, err := ParseEventLine(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, ok := e.(*KillEvent)
+	if !ok || !k.IsFriendlyFire() {
+		t.Fatalf("%q is not a team kill", line)
+	}
+	return k
+}
+
+func TestTeamKillTrackerThreshold(t *testing.T) {
+	var alerts []TeamKillAlert
+	tk := NewTeamKillTracker(TeamKillOptions{
+		Window:      time.Minute,
+		Threshold:   2,
+		OnThreshold: func(a TeamKillAlert) { alerts = append(alerts, a) },
+	})
+
+	for _, ts := range []string{"0:10", "1:20", "1:50", "2:00"} {
+		tk.ApplyEvent(friendlyKill(t, ts))
+	}
+	if len(alerts) != 2 || alerts[0].Count != 2 || alerts[1].Count != 3 {
+		t.Fatalf("alerts = %+v, want counts 2 and 3", alerts)
+	}
+	if alerts[0].GUID != "aa" || alerts[0].Name != "Alice" || alerts[0].ClientNum != 0 {
+		t.Errorf("alert attacker = %+v", alerts[0])
+	}
+
+	tk.ApplyEvent(&ServerEvent{BaseEvent: BaseEvent{Command: CmdInitGame}})
+	if n := tk.Count("aa", "Alice"); n != 0 {
+		t.Errorf("Count after InitGame = %d, want 0", n)
+	}
+}
+
+func TestTeamKillTrackerUntimedKillsExpire(t *testing.T) {
+	clk := newFakeClock()
+	tk := NewTeamKillTracker(TeamKillOptions{Window: time.Minute, Threshold: 1000})
+	tk.clock = clk
+
+	for i := 0; i < 3; i++ {
+		tk.ApplyEvent(friendlyKill(t, ""))
+		clk.Advance(20 * time.Second)
+	}
+	if n := tk.Count("aa", "Alice"); n != 3 {
+		t.Fatalf("Count = %d, want 3", n)
+	}
+
+	// Kills arriving every 20s against a one minute window never pile up.
+	for i := 0; i < 100; i++ {
+		tk.ApplyEvent(friendlyKill(t, ""))
+		if n := tk.Count("aa", "Alice"); n > 3 {
+			t.Fatalf("Count = %d after %d kills, want at most 3", n, i+4)
+		}
+		clk.Advance(20 * time.Second)
+	}
+}
+
+func TestTeamKillTrackerMaxAttackers(t *testing.T) {
+	tk := NewTeamKillTracker(TeamKillOptions{MaxAttackers: 2})
+	for i := 0; i < 3; i++ {
+		k := friendlyKill(t, "")
+		k.AttackerXUID = fmt.Sprintf("g%d", i)
+		tk.ApplyEvent(k)
+	}
+	if tk.Count("g0", "Alice") != 0 || tk.Count("g1", "Alice") != 1 || tk.Count("g2", "Alice") != 1 {
+		t.Errorf("oldest attacker not evicted")
+	}
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"sort"
+	"strings"
+)
+
+const Version = "0.2.0"
+
+const (
+	CmdInitGame     = "InitGame"
+	CmdShutdownGame = "ShutdownGame"
+	CmdJoin         = "J"
+	CmdQuit         = "Q"
+	CmdKill         = "K"
+	CmdDamage       = "D"
+	CmdSay          = "say"
+	CmdSayTeam      = "sayteam"
+	CmdTell         = "tell"
+	CmdWeapon       = "Weapon"
+	CmdItemPickup   = "ItemPickup"
+	CmdCallvote     = "Callvote"
+	CmdVote         = "Vote"
+	CmdScore        = "score"
+	CmdAward        = "Award"
+	CmdPrint        = "print"
+	CmdCenterPrint  = "centerprint"
+	CmdBroadcast    = "Broadcast"
+	CmdExit         = "Exit"
+)
+
+var builtinCommands = []string{
+	CmdInitGame,
+	CmdShutdownGame,
+	CmdJoin,
+	CmdQuit,
+	CmdKill,
+	CmdDamage,
+	CmdSay,
+	CmdSayTeam,
+	CmdTell,
+	CmdWeapon,
+	CmdItemPickup,
+	CmdCallvote,
+	CmdVote,
+	CmdScore,
+	CmdAward,
+	CmdPrint,
+	CmdCenterPrint,
+	CmdBroadcast,
+	CmdExit,
+}
+
+var canonicalCommands = func() map[string]string {
+	m := make(map[string]string, len(builtinCommands))
+	for _, cmd := range builtinCommands {
+		m[strings.ToLower(cmd)] = cmd
+	}
+	return m
+}()
+
+// normalizeCommand rewrites the command at the start of line, delimited by
+// ':', ';' or whitespace, to its canonical casing if it is a known command.
+func normalizeCommand(line string) string {
+	end := strings.IndexAny(line, ":; \t")
+	if end < 0 {
+		end = len(line)
+	}
+	cmd := line[:end]
+
+	canonical, ok := canonicalCommands[strings.ToLower(cmd)]
+	if !ok {
+		for _, registered := range registeredCommands() {
+			if strings.EqualFold(registered, cmd) {
+				canonical, ok = registered, true
+				break
+			}
+		}
+	}
+	if !ok || canonical == cmd {
+		return line
+	}
+	return canonical + line[end:]
+}
+
+// SupportedCommands returns, sorted, the commands the parser produces typed
+// events for, including any registered at runtime.
+func SupportedCommands() []string {
+	cmds := append([]string(nil), builtinCommands...)
+	cmds = append(cmds, registeredCommands()...)
+	sort.Strings(cmds)
+	return cmds
+}
+
+// registeredCommands returns the objective, voice and spawn commands, built
+// in or registered at runtime.
+func registeredCommands() []string {
+	var cmds []string
+
+	objectiveMu.RLock()
+	for cmd := range objectiveCommands {
+		cmds = append(cmds, cmd)
+	}
+	objectiveMu.RUnlock()
+
+	voiceMu.RLock()
+	for cmd := range voiceCommands {
+		cmds = append(cmds, cmd)
+	}
+	voiceMu.RUnlock()
+
+	spawnMu.RLock()
+	for cmd := range spawnCommands {
+		cmds = append(cmds, cmd)
+	}
+	spawnMu.RUnlock()
+
+	return cmds
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VoiceEvent is a voice quick-chat command, logged either as
+// "vsay <player> <token>" or as "vsay;guid;num;name;token". ClientNum is -1
+// and XUID empty for the text form. A missing token leaves Token empty.
+type VoiceEvent struct {
+	BaseEvent
+	XUID      string
+	ClientNum int
+	Player    string
+	Token     string
+}
+
+var (
+	voiceMu       sync.RWMutex
+	voiceCommands = map[string]bool{
+		"vsay":      true,
+		"vsay_team": true,
+	}
+)
+
+// RegisterVoiceCommand makes lines starting with cmd parse as VoiceEvents,
+// for engines with other quick-chat prefixes.
+func RegisterVoiceCommand(cmd string) {
+	voiceMu.Lock()
+	voiceCommands[cmd] = true
+	voiceMu.Unlock()
+}
+
+func isVoiceCommand(cmd string) bool {
+	voiceMu.RLock()
+	ok := voiceCommands[cmd]
+	voiceMu.RUnlock()
+	return ok
+}
+
+func parseVoiceEvent(line string, ts *time.Duration, raw string, base int) (*VoiceEvent, error) {
+	end := strings.IndexAny(line, " ;")
+	if end < 0 {
+		end = len(line)
+	}
+	cmd := line[:end]
+	if !isVoiceCommand(cmd) {
+		return nil, fmt.Errorf("not a voice event")
+	}
+
+	ev := &VoiceEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   cmd,
+			Raw:       raw,
+		},
+		ClientNum: -1,
+	}
+
+	if end < len(line) && line[end] == ';' {
+		parts := strings.SplitN(line, ";", 5)
+		if len(parts) < 4 {
+			return nil, fmt.Errorf("invalid voice event line: %q", line)
+		}
+		clientNum, err := parseClientNum(parts[2], base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client number %q: %w", parts[2], err)
+		}
+		ev.XUID = parts[1]
+		ev.ClientNum = clientNum
+		ev.Player = parts[3]
+		if len(parts) == 5 {
+			ev.Token = strings.TrimSpace(parts[4])
+		}
+		return ev, nil
+	}
+
+	fields := strings.Fields(line[end:])
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid voice event line: %q", line)
+	}
+	ev.Player = fields[0]
+	if len(fields) > 1 {
+		ev.Token = strings.Join(fields[1:], " ")
+	}
+	return ev, nil
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type VoteEvent struct {
+	BaseEvent
+	ClientNum int
+
+	// VoteType and VoteArg are set for Callvote lines; VoteArg is empty for
+	// votes that take no argument, such as map_restart.
+	VoteType string
+	VoteArg  string
+
+	// Choice is set for Vote lines to the lowercased choice, e.g. "yes".
+	Choice string
+}
+
+func parseVoteEvent(line string, ts *time.Duration, raw string) (*VoteEvent, error) {
+	cmd, rest, ok := strings.Cut(line, ":")
+	if !ok || (cmd != CmdCallvote && cmd != CmdVote) {
+		return nil, fmt.Errorf("not a vote event")
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid vote event line: %q", line)
+	}
+
+	clientNum, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client number %q: %w", fields[0], err)
+	}
+	if clientNum < 0 || clientNum > maxStrictClientNum {
+		return nil, fmt.Errorf("client number %d out of range", clientNum)
+	}
+
+	ev := &VoteEvent{
+		BaseEvent: BaseEvent{
+			Timestamp: ts,
+			Command:   cmd,
+			Raw:       raw,
+		},
+		ClientNum: clientNum,
+	}
+
+	if cmd == CmdVote {
+		ev.Choice = strings.ToLower(fields[1])
+		return ev, nil
+	}
+
+	ev.VoteType = fields[1]
+	ev.VoteArg = strings.Join(fields[2:], " ")
+	return ev, nil
+}
@@ -0,0 +1,44 @@
+package events
+
+import "strings"
+
+// Weapon is the raw weapon token from a log line, e.g. "m4_silencer_mp" or
+// "dsr50_mp+steadyaim+silencer".
+type Weapon string
+
+var weaponSuffixes = []string{"_mp", "_sp", "_zm"}
+
+var weaponAttachments = map[string]bool{
+	"acog": true, "akimbo": true, "dualclip": true, "dw": true, "eotech": true,
+	"extbarrel": true, "extclip": true, "fastads": true, "fastreload": true,
+	"fmj": true, "gl": true, "grip": true, "heartbeat": true, "holo": true,
+	"ir": true, "is": true, "mms": true, "reflex": true, "rf": true, "rof": true,
+	"sf": true, "shotgun": true, "silencer": true, "stalker": true,
+	"steadyaim": true, "swayreduc": true, "tactical": true, "thermal": true,
+	"vzoom": true, "xmags": true,
+}
+
+func (w Weapon) String() string { return string(w) }
+
+// Base strips "+attachment" lists, the _mp/_sp/_zm suffix and known
+// "_attachment" infixes, so "m4_grip_mp" and "m4_mp+grip" both become "m4".
+// Tokens that do not follow the convention are returned unchanged.
+func (w Weapon) Base() string {
+	s := string(w)
+	if i := strings.IndexByte(s, '+'); i > 0 {
+		s = s[:i]
+	}
+	for _, suffix := range weaponSuffixes {
+		if len(s) > len(suffix) && strings.HasSuffix(s, suffix) {
+			s = strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+
+	parts := strings.Split(s, "_")
+	n := len(parts)
+	for n > 1 && weaponAttachments[parts[n-1]] {
+		n--
+	}
+	return strings.Join(parts[:n], "_")
+}
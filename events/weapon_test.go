@@ -0,0 +1,31 @@
+package events
+
+import "testing"
+
+func TestWeaponBase(t *testing.T) {
+	tests := map[Weapon]string{
+		"m4_mp":                       "m4",
+		"m4_grip_mp":                  "m4",
+		"m4_mp+grip":                  "m4",
+		"dsr50_mp+steadyaim+silencer": "dsr50",
+		"ak47_acog_silencer_mp":       "ak47",
+		"knife_ballistic_mp":          "knife_ballistic",
+		"ray_gun_zm":                  "ray_gun",
+		"frag_grenade_sp":             "frag_grenade",
+		"_mp":                         "_mp",
+		"none":                        "none",
+		"":                            "",
+	}
+	for w, want := range tests {
+		if got := w.Base(); got != want {
+			t.Errorf("Weapon(%q).Base() = %q, want %q", w, got, want)
+		}
+	}
+}
+
+func TestKillWeaponKeepsRawToken(t *testing.T) {
+	k := parseKill(t, "K;aa;0;axis;Alice;bb;1;allies;Bob;an94_mp+reflex+fmj;100;MOD_RIFLE_BULLET;head")
+	if k.Weapon != "an94_mp+reflex+fmj" || k.Weapon.Base() != "an94" {
+		t.Errorf("Weapon = %q (base %q)", k.Weapon, k.Weapon.Base())
+	}
+}